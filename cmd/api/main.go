@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"tutup-lapak/internal/config"
 	"tutup-lapak/pkg/dotenv"
 
 	"github.com/labstack/echo/v4"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	env, err := dotenv.LoadEnv()
 	if err != nil {
@@ -16,22 +24,42 @@ func main() {
 		return
 	}
 
+	// ctx is canceled on SIGINT or SIGTERM, which stops the background jobs
+	// and shuts the server down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	log := config.NewLogger()
 	validator := config.NewValidator()
 	app := echo.New()
 	s3Uploader := config.NewS3Uploader(env)
+	jwtKeys := config.NewJWTKeySet(env)
 	pg := config.NewDatabase(log)
 	defer pg.Pool.Close()
 
 	config.Bootstrap(&config.BootstrapConfig{
+		Ctx:        ctx,
 		App:        app,
 		DB:         pg,
 		Log:        log,
 		Validator:  validator,
 		S3Uploader: s3Uploader,
+		JWTKeys:    jwtKeys,
 		Env:        env,
 	})
 
 	PORT := os.Getenv("PORT")
-	log.Fatal(app.Start(PORT))
+	go func() {
+		if err := app.Start(PORT); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Error("failed to shut down server")
+	}
 }
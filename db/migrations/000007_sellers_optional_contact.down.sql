@@ -0,0 +1,7 @@
+-- Drop constraint
+ALTER TABLE sellers DROP CONSTRAINT IF EXISTS sellers_email_or_phone_check;
+
+-- Restore NOT NULL
+ALTER TABLE sellers
+    ALTER COLUMN email SET NOT NULL,
+    ALTER COLUMN phone_number SET NOT NULL;
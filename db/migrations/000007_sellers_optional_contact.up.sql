@@ -0,0 +1,5 @@
+-- Allow sellers to register with either an email or a phone number
+ALTER TABLE sellers
+    ALTER COLUMN email DROP NOT NULL,
+    ALTER COLUMN phone_number DROP NOT NULL,
+    ADD CONSTRAINT sellers_email_or_phone_check CHECK (email IS NOT NULL OR phone_number IS NOT NULL);
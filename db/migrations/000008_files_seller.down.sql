@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_files_seller_id;
+
+-- Drop column
+ALTER TABLE files DROP COLUMN IF EXISTS seller_id;
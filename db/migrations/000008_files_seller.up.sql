@@ -0,0 +1,5 @@
+-- Track which seller uploaded a file
+ALTER TABLE files ADD COLUMN seller_id BIGINT REFERENCES sellers(id) ON DELETE SET NULL;
+
+-- Create indexes
+CREATE INDEX idx_files_seller_id ON files(seller_id);
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+DROP INDEX IF EXISTS idx_sessions_seller_id;
+
+-- DROP refresh_tokens and sessions
+DROP TABLE IF EXISTS refresh_tokens CASCADE;
+DROP TABLE IF EXISTS sessions CASCADE;
@@ -0,0 +1,23 @@
+-- Create table sessions, each session is one refresh token family
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    seller_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE
+);
+
+-- Create table refresh_tokens
+CREATE TABLE refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    session_id UUID NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_sessions_seller_id ON sessions(seller_id);
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
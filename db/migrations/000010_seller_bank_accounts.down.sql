@@ -0,0 +1,11 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_purchase_payment_details_purchase_id;
+DROP INDEX IF EXISTS idx_seller_bank_accounts_default;
+DROP INDEX IF EXISTS idx_seller_bank_accounts_seller_id;
+
+-- DROP trigger
+DROP TRIGGER IF EXISTS set_timestamp_seller_bank_accounts ON seller_bank_accounts CASCADE;
+
+-- DROP purchase_payment_details and seller_bank_accounts
+DROP TABLE IF EXISTS purchase_payment_details CASCADE;
+DROP TABLE IF EXISTS seller_bank_accounts CASCADE;
@@ -0,0 +1,48 @@
+-- Create table seller_bank_accounts
+CREATE TABLE seller_bank_accounts (
+    id BIGSERIAL PRIMARY KEY,
+    seller_id BIGINT NOT NULL,
+    bank_account_name VARCHAR(255) NOT NULL,
+    bank_account_holder VARCHAR(255) NOT NULL,
+    bank_account_number VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE
+);
+
+-- Create triggers
+CREATE TRIGGER set_timestamp_seller_bank_accounts
+    BEFORE UPDATE ON seller_bank_accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Create indexes
+CREATE INDEX idx_seller_bank_accounts_seller_id ON seller_bank_accounts(seller_id);
+CREATE UNIQUE INDEX idx_seller_bank_accounts_default ON seller_bank_accounts(seller_id) WHERE is_default;
+
+-- Move existing bank details as the default account
+INSERT INTO seller_bank_accounts (seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default)
+SELECT id, bank_account_name, bank_account_holder, bank_account_number, TRUE
+FROM sellers
+WHERE bank_account_name IS NOT NULL
+    AND bank_account_holder IS NOT NULL
+    AND bank_account_number IS NOT NULL;
+
+-- Create table purchase_payment_details, a snapshot of the account each seller is paid to
+CREATE TABLE purchase_payment_details (
+    id BIGSERIAL PRIMARY KEY,
+    purchase_id BIGINT NOT NULL,
+    seller_id BIGINT NOT NULL,
+    bank_account_id BIGINT,
+    bank_account_name VARCHAR(255) NOT NULL,
+    bank_account_holder VARCHAR(255) NOT NULL,
+    bank_account_number VARCHAR(255) NOT NULL,
+    total_price INT NOT NULL,
+    FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE CASCADE,
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE,
+    FOREIGN KEY (bank_account_id) REFERENCES seller_bank_accounts(id) ON DELETE SET NULL
+);
+
+-- Create indexes
+CREATE INDEX idx_purchase_payment_details_purchase_id ON purchase_payment_details(purchase_id);
@@ -0,0 +1,7 @@
+-- Drop columns
+ALTER TABLE sellers
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS role;
+
+-- DROP enum
+DROP TYPE IF EXISTS enum_seller_roles CASCADE;
@@ -0,0 +1,11 @@
+-- Create enum
+CREATE TYPE enum_seller_roles as ENUM (
+    'seller',
+    'admin',
+    'support'
+);
+
+-- Add role and disabled flag to sellers
+ALTER TABLE sellers
+    ADD COLUMN role enum_seller_roles NOT NULL DEFAULT 'seller',
+    ADD COLUMN disabled_at TIMESTAMPTZ;
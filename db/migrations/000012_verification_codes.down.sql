@@ -0,0 +1,13 @@
+-- Drop columns
+ALTER TABLE sellers
+    DROP COLUMN IF EXISTS phone_verified_at,
+    DROP COLUMN IF EXISTS email_verified_at;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_verification_codes_seller_id_purpose;
+
+-- DROP verification_codes
+DROP TABLE IF EXISTS verification_codes CASCADE;
+
+-- DROP enum
+DROP TYPE IF EXISTS enum_verification_purposes CASCADE;
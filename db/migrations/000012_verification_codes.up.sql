@@ -0,0 +1,28 @@
+-- Create enum
+CREATE TYPE enum_verification_purposes as ENUM (
+    'password_reset',
+    'verify_email',
+    'verify_phone'
+);
+
+-- Create table verification_codes
+CREATE TABLE verification_codes (
+    id BIGSERIAL PRIMARY KEY,
+    seller_id BIGINT NOT NULL,
+    purpose enum_verification_purposes NOT NULL,
+    target VARCHAR(255) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_verification_codes_seller_id_purpose ON verification_codes(seller_id, purpose);
+
+-- Track verified contacts
+ALTER TABLE sellers
+    ADD COLUMN email_verified_at TIMESTAMPTZ,
+    ADD COLUMN phone_verified_at TIMESTAMPTZ;
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_security_events_created_at;
+DROP INDEX IF EXISTS idx_security_events_seller_id;
+DROP INDEX IF EXISTS idx_rate_limits_last_failure_at;
+
+-- DROP security_events and rate_limits
+DROP TABLE IF EXISTS security_events CASCADE;
+DROP TABLE IF EXISTS rate_limits CASCADE;
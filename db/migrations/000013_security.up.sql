@@ -0,0 +1,24 @@
+-- Create table rate_limits, failure counters keyed by account or client IP
+CREATE TABLE rate_limits (
+    key VARCHAR(255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ
+);
+
+-- Create table security_events
+CREATE TABLE security_events (
+    id BIGSERIAL PRIMARY KEY,
+    seller_id BIGINT,
+    event_type VARCHAR(64) NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE SET NULL
+);
+
+-- Create indexes
+CREATE INDEX idx_rate_limits_last_failure_at ON rate_limits(last_failure_at);
+CREATE INDEX idx_security_events_seller_id ON security_events(seller_id);
+CREATE INDEX idx_security_events_created_at ON security_events(created_at);
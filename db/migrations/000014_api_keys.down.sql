@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_api_keys_seller_id;
+
+-- DROP api_keys
+DROP TABLE IF EXISTS api_keys CASCADE;
@@ -0,0 +1,7 @@
+-- DROP trigger
+DROP TRIGGER IF EXISTS increment_version_products ON products CASCADE;
+DROP FUNCTION IF EXISTS trigger_increment_version CASCADE;
+
+-- Drop version from products
+ALTER TABLE products
+    DROP COLUMN IF EXISTS version;
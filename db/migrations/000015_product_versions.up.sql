@@ -0,0 +1,18 @@
+-- Add version to products
+ALTER TABLE products
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- Create version trigger function
+CREATE OR REPLACE FUNCTION trigger_increment_version()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.version = OLD.version + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create version trigger
+CREATE TRIGGER increment_version_products
+    BEFORE UPDATE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
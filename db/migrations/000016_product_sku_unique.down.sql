@@ -0,0 +1,2 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_seller_id_sku;
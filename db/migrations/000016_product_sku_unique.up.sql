@@ -0,0 +1,41 @@
+-- Rename duplicated skus, the oldest product of each seller keeps its sku.
+-- Renamed skus stay within the 32 characters the API accepts and retry with a
+-- counter until nothing else of the seller holds them.
+DO $$
+DECLARE
+    dup RECORD;
+    suffix TEXT;
+    candidate TEXT;
+    attempt INT;
+BEGIN
+    FOR dup IN
+        SELECT p.id, p.seller_id, p.sku
+        FROM products p
+        WHERE EXISTS (
+            SELECT 1 FROM products o
+            WHERE o.seller_id = p.seller_id AND o.sku = p.sku AND o.id < p.id
+        )
+        ORDER BY p.id
+    LOOP
+        attempt := 0;
+        LOOP
+            suffix := '-' || dup.id;
+            IF attempt > 0 THEN
+                suffix := suffix || '-' || attempt;
+            END IF;
+            candidate := left(dup.sku, 32 - length(suffix)) || suffix;
+
+            EXIT WHEN NOT EXISTS (
+                SELECT 1 FROM products
+                WHERE seller_id = dup.seller_id AND sku = candidate
+            );
+            attempt := attempt + 1;
+        END LOOP;
+
+        UPDATE products SET sku = candidate WHERE id = dup.id;
+    END LOOP;
+END;
+$$;
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_products_seller_id_sku ON products(seller_id, sku);
@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_search_vector;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+
+-- Drop description and search vector from products
+ALTER TABLE products
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS description;
@@ -0,0 +1,14 @@
+-- Create extension
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Add description and search vector to products
+ALTER TABLE products
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name), 'A') ||
+        setweight(to_tsvector('simple', description), 'B')
+    ) STORED;
+
+-- Create indexes
+CREATE INDEX idx_products_search_vector ON products USING GIN(search_vector);
+CREATE INDEX idx_products_name_trgm ON products USING GIN(name gin_trgm_ops);
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_price;
+DROP INDEX IF EXISTS idx_products_category_price;
+DROP INDEX IF EXISTS idx_products_seller_id_price;
+DROP INDEX IF EXISTS idx_products_in_stock_category_price;
@@ -0,0 +1,5 @@
+-- Create indexes
+CREATE INDEX idx_products_price ON products(price);
+CREATE INDEX idx_products_category_price ON products(category, price);
+CREATE INDEX idx_products_seller_id_price ON products(seller_id, price);
+CREATE INDEX idx_products_in_stock_category_price ON products(category, price) WHERE qty > 0;
@@ -0,0 +1,9 @@
+-- DROP trigger
+DROP TRIGGER IF EXISTS rollup_product_sales_purchases ON purchases CASCADE;
+DROP FUNCTION IF EXISTS trigger_rollup_product_sales CASCADE;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_sales_hourly_bucket;
+
+-- DROP product_sales_hourly
+DROP TABLE IF EXISTS product_sales_hourly CASCADE;
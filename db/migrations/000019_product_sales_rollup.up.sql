@@ -0,0 +1,40 @@
+-- Create table product_sales_hourly
+CREATE TABLE product_sales_hourly (
+    product_id BIGINT NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    qty BIGINT NOT NULL,
+    PRIMARY KEY (product_id, bucket),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_product_sales_hourly_bucket ON product_sales_hourly(bucket, product_id) INCLUDE (qty);
+
+-- Create rollup trigger function
+CREATE OR REPLACE FUNCTION trigger_rollup_product_sales()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO product_sales_hourly (product_id, bucket, qty)
+  SELECT ppp.product_id, date_trunc('hour', NEW.paid_at), SUM(ppp.qty)
+  FROM pivot_purchase_products ppp
+  WHERE ppp.purchase_id = NEW.id
+  GROUP BY ppp.product_id
+  ON CONFLICT (product_id, bucket) DO UPDATE SET qty = product_sales_hourly.qty + EXCLUDED.qty;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create rollup trigger, a purchase counts once when it is first paid
+CREATE TRIGGER rollup_product_sales_purchases
+    AFTER UPDATE OF paid_at ON purchases
+    FOR EACH ROW
+    WHEN (OLD.paid_at IS NULL AND NEW.paid_at IS NOT NULL)
+    EXECUTE FUNCTION trigger_rollup_product_sales();
+
+-- Backfill paid purchases
+INSERT INTO product_sales_hourly (product_id, bucket, qty)
+SELECT ppp.product_id, date_trunc('hour', pu.paid_at), SUM(ppp.qty)
+FROM pivot_purchase_products ppp
+JOIN purchases pu ON pu.id = ppp.purchase_id
+WHERE pu.paid_at IS NOT NULL
+GROUP BY ppp.product_id, date_trunc('hour', pu.paid_at);
@@ -0,0 +1,14 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_deleted_at;
+DROP INDEX IF EXISTS idx_products_seller_id_sku;
+
+-- Purge soft deleted products
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+
+-- Drop deleted_at from products
+ALTER TABLE products
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS deleted_at;
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_products_seller_id_sku ON products(seller_id, sku);
@@ -0,0 +1,9 @@
+-- Add deleted_at to products, moderated_at marks admin takedowns
+ALTER TABLE products
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN moderated_at TIMESTAMPTZ;
+
+-- Create indexes, deleted products release their sku
+DROP INDEX IF EXISTS idx_products_seller_id_sku;
+CREATE UNIQUE INDEX idx_products_seller_id_sku ON products(seller_id, sku) WHERE deleted_at IS NULL;
+CREATE INDEX idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_files_file_id;
+
+-- DROP product_files
+DROP TABLE IF EXISTS product_files CASCADE;
@@ -0,0 +1,18 @@
+-- Create table product_files
+CREATE TABLE product_files (
+    product_id BIGINT NOT NULL,
+    file_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, file_id),
+    UNIQUE (product_id, position),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_product_files_file_id ON product_files(file_id);
+
+-- Backfill the current file of every product as its primary image
+INSERT INTO product_files (product_id, file_id, position)
+SELECT id, file_id, 0 FROM products;
@@ -0,0 +1,15 @@
+-- Drop variant from pivot_purchase_products
+ALTER TABLE pivot_purchase_products
+    DROP COLUMN IF EXISTS variant_id;
+
+-- DROP trigger
+DROP TRIGGER IF EXISTS rollup_product_variants ON product_variants CASCADE;
+DROP FUNCTION IF EXISTS trigger_rollup_product_variants CASCADE;
+DROP TRIGGER IF EXISTS set_timestamp_product_variants ON product_variants CASCADE;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_variants_product_id_sku;
+DROP INDEX IF EXISTS idx_product_variants_product_id;
+
+-- DROP product_variants
+DROP TABLE IF EXISTS product_variants CASCADE;
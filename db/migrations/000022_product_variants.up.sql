@@ -0,0 +1,60 @@
+-- Create table product_variants
+CREATE TABLE product_variants (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    size VARCHAR(64),
+    color VARCHAR(64),
+    qty INT NOT NULL,
+    price INT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Create triggers
+CREATE TRIGGER set_timestamp_product_variants
+    BEFORE UPDATE ON product_variants
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Create indexes
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+CREATE UNIQUE INDEX idx_product_variants_product_id_sku ON product_variants(product_id, sku) WHERE deleted_at IS NULL;
+
+-- Create variant rollup trigger function, a product with variants lists
+-- their total qty and lowest price so existing filters and sorts apply. A
+-- product whose last variant is deleted drops to qty 0 and keeps its price.
+CREATE OR REPLACE FUNCTION trigger_rollup_product_variants()
+RETURNS TRIGGER AS $$
+DECLARE
+  target_product_id BIGINT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    target_product_id = OLD.product_id;
+  ELSE
+    target_product_id = NEW.product_id;
+  END IF;
+
+  UPDATE products p
+  SET qty = v.qty, price = COALESCE(v.price, p.price)
+  FROM (
+    SELECT COALESCE(SUM(qty), 0) qty, MIN(price) price
+    FROM product_variants
+    WHERE product_id = target_product_id AND deleted_at IS NULL
+  ) v
+  WHERE p.id = target_product_id AND (p.qty <> v.qty OR p.price <> COALESCE(v.price, p.price));
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER rollup_product_variants
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_rollup_product_variants();
+
+-- Add variant to pivot_purchase_products
+ALTER TABLE pivot_purchase_products
+    ADD COLUMN variant_id BIGINT REFERENCES product_variants(id) ON DELETE SET NULL;
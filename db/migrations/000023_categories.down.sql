@@ -0,0 +1,42 @@
+-- Create enum, fails while products use categories other than these
+CREATE TYPE enum_product_categories as ENUM (
+    'Food',
+    'Beverage',
+    'Clothes',
+    'Furniture',
+    'Tools'
+);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_in_stock_category_price;
+DROP INDEX IF EXISTS idx_products_category_price;
+
+-- Replace the category reference of products by the enum
+ALTER TABLE products
+    ADD COLUMN category enum_product_categories;
+
+ALTER TABLE products DISABLE TRIGGER set_timestamp_products, DISABLE TRIGGER increment_version_products;
+
+UPDATE products p
+SET category = c.name::enum_product_categories
+FROM categories c
+WHERE c.id = p.category_id;
+
+ALTER TABLE products ENABLE TRIGGER set_timestamp_products, ENABLE TRIGGER increment_version_products;
+
+ALTER TABLE products
+    ALTER COLUMN category SET NOT NULL,
+    DROP COLUMN IF EXISTS category_id;
+
+-- Create indexes
+CREATE INDEX idx_products_category_price ON products(category, price);
+CREATE INDEX idx_products_in_stock_category_price ON products(category, price) WHERE qty > 0;
+
+-- DROP trigger
+DROP TRIGGER IF EXISTS set_timestamp_categories ON categories CASCADE;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_categories_parent_id;
+
+-- DROP categories
+DROP TABLE IF EXISTS categories CASCADE;
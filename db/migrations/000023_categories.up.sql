@@ -0,0 +1,54 @@
+-- Create table categories
+CREATE TABLE categories (
+    id BIGSERIAL PRIMARY KEY,
+    parent_id BIGINT,
+    name VARCHAR(64) UNIQUE NOT NULL,
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE RESTRICT
+);
+
+-- Create triggers
+CREATE TRIGGER set_timestamp_categories
+    BEFORE UPDATE ON categories
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Create indexes
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- Seed the categories of the former enum
+INSERT INTO categories (name, slug) VALUES
+    ('Food', 'food'),
+    ('Beverage', 'beverage'),
+    ('Clothes', 'clothes'),
+    ('Furniture', 'furniture'),
+    ('Tools', 'tools');
+
+-- Reference categories by id, renaming a category leaves products untouched
+ALTER TABLE products
+    ADD COLUMN category_id BIGINT;
+
+-- Backfill without bumping updated_at and version of every product
+ALTER TABLE products DISABLE TRIGGER set_timestamp_products, DISABLE TRIGGER increment_version_products;
+
+UPDATE products p
+SET category_id = c.id
+FROM categories c
+WHERE c.name = p.category::TEXT;
+
+ALTER TABLE products ENABLE TRIGGER set_timestamp_products, ENABLE TRIGGER increment_version_products;
+
+ALTER TABLE products
+    ALTER COLUMN category_id SET NOT NULL,
+    ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT,
+    DROP COLUMN category;
+
+-- Create indexes, replacing the ones on the dropped category column
+CREATE INDEX idx_products_category_price ON products(category_id, price);
+CREATE INDEX idx_products_in_stock_category_price ON products(category_id, price) WHERE qty > 0;
+
+-- DROP enum
+DROP TYPE IF EXISTS enum_product_categories;
@@ -0,0 +1,16 @@
+-- DROP trigger
+DROP TRIGGER IF EXISTS record_price_history_products ON products CASCADE;
+DROP FUNCTION IF EXISTS trigger_record_price_history CASCADE;
+DROP TRIGGER IF EXISTS set_timestamp_product_price_schedules ON product_price_schedules CASCADE;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_price_history_product_id_created_at;
+DROP INDEX IF EXISTS idx_product_price_schedules_ending;
+DROP INDEX IF EXISTS idx_product_price_schedules_due;
+
+-- DROP tables
+DROP TABLE IF EXISTS product_price_history CASCADE;
+DROP TABLE IF EXISTS product_price_schedules CASCADE;
+
+-- DROP enum
+DROP TYPE IF EXISTS enum_price_schedule_status CASCADE;
@@ -0,0 +1,89 @@
+-- Create extension
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Create enum
+CREATE TYPE enum_price_schedule_status as ENUM (
+    'pending',
+    'active',
+    'completed',
+    'expired',
+    'canceled'
+);
+
+-- Create table product_price_schedules, an open ended schedule changes the
+-- price for good
+CREATE TABLE product_price_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    price INT NOT NULL,
+    previous_price INT,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,
+    status enum_price_schedule_status NOT NULL DEFAULT 'pending',
+    created_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES sellers(id) ON DELETE SET NULL,
+    CONSTRAINT chk_product_price_schedules_window CHECK (ends_at IS NULL OR ends_at > starts_at),
+    CONSTRAINT excl_product_price_schedules_overlap EXCLUDE USING gist (
+        product_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (status IN ('pending', 'active'))
+);
+
+-- Create table product_price_history
+CREATE TABLE product_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    old_price INT,
+    new_price INT NOT NULL,
+    changed_by BIGINT,
+    schedule_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES sellers(id) ON DELETE SET NULL,
+    FOREIGN KEY (schedule_id) REFERENCES product_price_schedules(id) ON DELETE SET NULL
+);
+
+-- Create triggers
+CREATE TRIGGER set_timestamp_product_price_schedules
+    BEFORE UPDATE ON product_price_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Create indexes
+CREATE INDEX idx_product_price_schedules_due ON product_price_schedules(starts_at) WHERE status = 'pending';
+CREATE INDEX idx_product_price_schedules_ending ON product_price_schedules(ends_at) WHERE status = 'active';
+CREATE INDEX idx_product_price_history_product_id_created_at ON product_price_history(product_id, created_at DESC);
+
+-- Create price history trigger function, writers name the author of a change
+-- with the transaction settings app.price_changed_by and app.price_schedule_id
+CREATE OR REPLACE FUNCTION trigger_record_price_history()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND OLD.price = NEW.price THEN
+    RETURN NULL;
+  END IF;
+
+  INSERT INTO product_price_history (product_id, old_price, new_price, changed_by, schedule_id)
+  VALUES (
+    NEW.id,
+    CASE WHEN TG_OP = 'UPDATE' THEN OLD.price END,
+    NEW.price,
+    NULLIF(current_setting('app.price_changed_by', TRUE), '')::BIGINT,
+    NULLIF(current_setting('app.price_schedule_id', TRUE), '')::BIGINT
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_price_history_products
+    AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_record_price_history();
+
+-- Backfill the current price of every product
+INSERT INTO product_price_history (product_id, new_price, changed_by, created_at)
+SELECT id, price, seller_id, created_at
+FROM products;
@@ -0,0 +1,23 @@
+-- Drop subtotal and discount
+ALTER TABLE purchase_payment_details
+    DROP COLUMN IF EXISTS subtotal_price,
+    DROP COLUMN IF EXISTS discount;
+
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS subtotal_price,
+    DROP COLUMN IF EXISTS discount;
+
+-- DROP trigger
+DROP TRIGGER IF EXISTS set_timestamp_promotions ON promotions CASCADE;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_purchase_discounts_purchase_id;
+DROP INDEX IF EXISTS idx_promotions_seller_id;
+DROP INDEX IF EXISTS idx_promotions_code;
+
+-- DROP tables
+DROP TABLE IF EXISTS purchase_discounts CASCADE;
+DROP TABLE IF EXISTS promotions CASCADE;
+
+-- DROP enum
+DROP TYPE IF EXISTS enum_promotion_discount_types CASCADE;
@@ -0,0 +1,79 @@
+-- Create enum
+CREATE TYPE enum_promotion_discount_types as ENUM (
+    'percentage',
+    'fixed'
+);
+
+-- Create table promotions, a promotion without code applies by itself while
+-- one with code is a voucher entered at checkout. It covers one product, one
+-- category or the whole catalog of its seller.
+CREATE TABLE promotions (
+    id BIGSERIAL PRIMARY KEY,
+    seller_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    code VARCHAR(32),
+    discount_type enum_promotion_discount_types NOT NULL,
+    discount_value INT NOT NULL,
+    max_discount INT,
+    product_id BIGINT,
+    category VARCHAR(64),
+    min_spend INT NOT NULL DEFAULT 0,
+    usage_limit INT,
+    usage_count INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (category) REFERENCES categories(name) ON UPDATE CASCADE,
+    CONSTRAINT chk_promotions_scope CHECK (product_id IS NULL OR category IS NULL),
+    CONSTRAINT chk_promotions_value CHECK (discount_value > 0 AND (discount_type = 'fixed' OR discount_value <= 100)),
+    CONSTRAINT chk_promotions_usage CHECK (usage_limit IS NULL OR usage_count <= usage_limit),
+    CONSTRAINT chk_promotions_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- Create table purchase_discounts, the promotions redeemed by a purchase
+CREATE TABLE purchase_discounts (
+    id BIGSERIAL PRIMARY KEY,
+    purchase_id BIGINT NOT NULL,
+    promotion_id BIGINT,
+    seller_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    code VARCHAR(32),
+    amount INT NOT NULL,
+    FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE CASCADE,
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE SET NULL,
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE
+);
+
+-- Create triggers
+CREATE TRIGGER set_timestamp_promotions
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_promotions_code ON promotions(code) WHERE code IS NOT NULL;
+CREATE INDEX idx_promotions_seller_id ON promotions(seller_id) WHERE code IS NULL AND is_active;
+CREATE INDEX idx_purchase_discounts_purchase_id ON purchase_discounts(purchase_id);
+
+-- Split totals into subtotal and discount, total_price stays the amount to pay
+ALTER TABLE purchases
+    ADD COLUMN subtotal_price INT,
+    ADD COLUMN discount INT NOT NULL DEFAULT 0;
+
+UPDATE purchases SET subtotal_price = total_price;
+
+ALTER TABLE purchases
+    ALTER COLUMN subtotal_price SET NOT NULL;
+
+ALTER TABLE purchase_payment_details
+    ADD COLUMN subtotal_price INT,
+    ADD COLUMN discount INT NOT NULL DEFAULT 0;
+
+UPDATE purchase_payment_details SET subtotal_price = total_price;
+
+ALTER TABLE purchase_payment_details
+    ALTER COLUMN subtotal_price SET NOT NULL;
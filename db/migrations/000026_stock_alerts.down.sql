@@ -0,0 +1,11 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_stock_alerts_pending;
+DROP INDEX IF EXISTS idx_stock_alerts_seller_id;
+
+-- DROP tables
+DROP TABLE IF EXISTS stock_alerts CASCADE;
+
+-- Drop low stock threshold
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_low_stock_threshold,
+    DROP COLUMN IF EXISTS low_stock_threshold;
@@ -0,0 +1,27 @@
+-- Add low stock threshold, a seller is alerted once qty drops to it. The
+-- default of 0 alerts when a product sells out.
+ALTER TABLE products
+    ADD COLUMN low_stock_threshold INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_products_low_stock_threshold CHECK (low_stock_threshold >= 0);
+
+-- Create table stock_alerts, one row each time a paid purchase takes the qty
+-- of a product past its threshold. Undelivered alerts are retried until
+-- attempts runs out.
+CREATE TABLE stock_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    seller_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    purchase_id BIGINT,
+    qty INT NOT NULL,
+    threshold INT NOT NULL,
+    delivered_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (seller_id) REFERENCES sellers(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE SET NULL
+);
+
+-- Create indexes
+CREATE INDEX idx_stock_alerts_seller_id ON stock_alerts(seller_id, created_at DESC);
+CREATE INDEX idx_stock_alerts_pending ON stock_alerts(id) WHERE delivered_at IS NULL;
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type StockAlertQuery struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"min=0"`
+}
+
+type StockAlertResponse struct {
+	AlertID     string     `json:"alertId"`
+	ProductID   string     `json:"productId"`
+	Name        string     `json:"name"`
+	Sku         string     `json:"sku"`
+	PurchaseID  *string    `json:"purchaseId"`
+	Qty         int        `json:"qty"`
+	Threshold   int        `json:"threshold"`
+	DeliveredAt *time.Time `json:"deliveredAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// StockThresholdPayload sets the qty at which the seller is alerted, 0 only
+// alerts when the product sells out.
+type StockThresholdPayload struct {
+	LowStockThreshold *int `json:"lowStockThreshold" validate:"required,min=0,max=1000000"`
+}
+
+type StockThresholdResponse struct {
+	ProductID         string `json:"productId"`
+	Qty               int    `json:"qty"`
+	LowStockThreshold int    `json:"lowStockThreshold"`
+}
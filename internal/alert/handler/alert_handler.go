@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"tutup-lapak/internal/alert/dto"
+	"tutup-lapak/internal/alert/usecase"
+	custom_middleware "tutup-lapak/internal/middleware"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const DEFAULT_LIMIT = 5
+
+type AlertHandler struct {
+	UseCase  *usecase.AlertUsecase
+	Validate *validator.Validate
+}
+
+func NewAlertHandler(useCase *usecase.AlertUsecase, validate *validator.Validate) *AlertHandler {
+	return &AlertHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *AlertHandler) ListStockAlerts(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var query = new(dto.StockAlertQuery)
+
+	if err := ctx.Bind(query); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(query); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DEFAULT_LIMIT
+	}
+
+	alerts, err := h.UseCase.ListStockAlerts(ctx.Request().Context(), sellerID, query)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, alerts)
+}
+
+func (h *AlertHandler) SetStockThreshold(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.StockThresholdPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	threshold, err := h.UseCase.SetStockThreshold(ctx.Request().Context(), productID, sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, threshold)
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type StockAlert struct {
+	ID          int
+	SellerID    int
+	ProductID   int
+	ProductName string
+	ProductSku  string
+	PurchaseID  *int
+	Qty         int
+	Threshold   int
+	DeliveredAt *time.Time
+	CreatedAt   time.Time
+}
+
+// StockAlertDelivery is an alert along with the contact it is delivered to.
+type StockAlertDelivery struct {
+	StockAlert
+	SellerEmail *string
+}
+
+type StockThreshold struct {
+	ProductID         int
+	Qty               int
+	LowStockThreshold int
+}
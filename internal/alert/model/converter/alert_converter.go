@@ -0,0 +1,43 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/alert/dto"
+	"tutup-lapak/internal/alert/model"
+)
+
+func ToStockAlertResponse(alert model.StockAlert) dto.StockAlertResponse {
+	var purchaseID *string
+	if alert.PurchaseID != nil {
+		id := strconv.Itoa(*alert.PurchaseID)
+		purchaseID = &id
+	}
+
+	return dto.StockAlertResponse{
+		AlertID:     strconv.Itoa(alert.ID),
+		ProductID:   strconv.Itoa(alert.ProductID),
+		Name:        alert.ProductName,
+		Sku:         alert.ProductSku,
+		PurchaseID:  purchaseID,
+		Qty:         alert.Qty,
+		Threshold:   alert.Threshold,
+		DeliveredAt: alert.DeliveredAt,
+		CreatedAt:   alert.CreatedAt,
+	}
+}
+
+func ToStockAlertResponses(alerts []model.StockAlert) []dto.StockAlertResponse {
+	responses := make([]dto.StockAlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		responses = append(responses, ToStockAlertResponse(alert))
+	}
+	return responses
+}
+
+func ToStockThresholdResponse(threshold model.StockThreshold) dto.StockThresholdResponse {
+	return dto.StockThresholdResponse{
+		ProductID:         strconv.Itoa(threshold.ProductID),
+		Qty:               threshold.Qty,
+		LowStockThreshold: threshold.LowStockThreshold,
+	}
+}
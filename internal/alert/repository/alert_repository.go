@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+
+	"tutup-lapak/internal/alert/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AlertRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAlertRepository(pool *pgxpool.Pool) *AlertRepository {
+	return &AlertRepository{pool: pool}
+}
+
+const listStockAlertsQuery = `-- name: ListStockAlerts :many
+SELECT a.id, a.seller_id, a.product_id, p.name, p.sku, a.purchase_id, a.qty, a.threshold, a.delivered_at, a.created_at
+FROM stock_alerts a
+JOIN products p ON p.id = a.product_id
+WHERE a.seller_id = $1
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListStockAlertsParams struct {
+	SellerID int
+	Limit    int
+	Offset   int
+}
+
+func (r *AlertRepository) ListStockAlerts(ctx context.Context, arg ListStockAlertsParams) ([]model.StockAlert, error) {
+	rows, err := r.pool.Query(ctx, listStockAlertsQuery, arg.SellerID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.StockAlert
+	for rows.Next() {
+		i, err := scanStockAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingStockAlertsQuery = `-- name: ListPendingStockAlerts :many
+SELECT a.id, a.seller_id, a.product_id, p.name, p.sku, a.purchase_id, a.qty, a.threshold, a.delivered_at, a.created_at, s.email
+FROM stock_alerts a
+JOIN products p ON p.id = a.product_id
+JOIN sellers s ON s.id = a.seller_id
+WHERE a.delivered_at IS NULL AND a.attempts < $1
+ORDER BY a.id
+LIMIT $2
+`
+
+type ListPendingStockAlertsParams struct {
+	MaxAttempts int
+	Limit       int
+}
+
+// ListPendingStockAlerts returns the oldest alerts that are not delivered
+// yet and have attempts left.
+func (r *AlertRepository) ListPendingStockAlerts(ctx context.Context, arg ListPendingStockAlertsParams) ([]model.StockAlertDelivery, error) {
+	rows, err := r.pool.Query(ctx, listPendingStockAlertsQuery, arg.MaxAttempts, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.StockAlertDelivery
+	for rows.Next() {
+		var i model.StockAlertDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductID,
+			&i.ProductName,
+			&i.ProductSku,
+			&i.PurchaseID,
+			&i.Qty,
+			&i.Threshold,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.SellerEmail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markStockAlertDeliveredQuery = `-- name: MarkStockAlertDelivered :exec
+UPDATE stock_alerts SET delivered_at = NOW() WHERE id = $1
+`
+
+func (r *AlertRepository) MarkStockAlertDelivered(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, markStockAlertDeliveredQuery, id)
+	return err
+}
+
+const recordStockAlertAttemptQuery = `-- name: RecordStockAlertAttempt :exec
+UPDATE stock_alerts SET attempts = attempts + 1 WHERE id = $1
+`
+
+// RecordStockAlertAttempt counts a failed delivery of the alert.
+func (r *AlertRepository) RecordStockAlertAttempt(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, recordStockAlertAttemptQuery, id)
+	return err
+}
+
+const updateStockThresholdQuery = `-- name: UpdateStockThreshold :one
+UPDATE products
+SET low_stock_threshold = $3
+WHERE id = $1 AND seller_id = $2 AND deleted_at IS NULL
+RETURNING id, qty, low_stock_threshold
+`
+
+type UpdateStockThresholdParams struct {
+	ProductID         int
+	SellerID          int
+	LowStockThreshold int
+}
+
+// UpdateStockThreshold returns ErrNotFound when the product is not a live
+// product of the seller.
+func (r *AlertRepository) UpdateStockThreshold(ctx context.Context, arg UpdateStockThresholdParams) (model.StockThreshold, error) {
+	row := r.pool.QueryRow(ctx, updateStockThresholdQuery, arg.ProductID, arg.SellerID, arg.LowStockThreshold)
+	var i model.StockThreshold
+	err := row.Scan(
+		&i.ProductID,
+		&i.Qty,
+		&i.LowStockThreshold,
+	)
+	return i, err
+}
+
+func scanStockAlert(row pgx.Row) (model.StockAlert, error) {
+	var i model.StockAlert
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductID,
+		&i.ProductName,
+		&i.ProductSku,
+		&i.PurchaseID,
+		&i.Qty,
+		&i.Threshold,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"tutup-lapak/internal/alert/dto"
+	"tutup-lapak/internal/alert/model"
+	"tutup-lapak/internal/alert/model/converter"
+	"tutup-lapak/internal/alert/repository"
+	"tutup-lapak/pkg/notifier"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// alertDeliveryBatch is how many alerts one delivery run sends at most.
+	alertDeliveryBatch = 100
+	// alertMaxAttempts is how often an alert is sent before it is given up,
+	// it is still listed to the seller.
+	alertMaxAttempts = 5
+)
+
+type AlertUsecase struct {
+	repo     *repository.AlertRepository
+	channel  string
+	notifier notifier.Notifier
+	log      *logrus.Logger
+}
+
+func NewAlertUsecase(repo *repository.AlertRepository, channel string, notifier notifier.Notifier, log *logrus.Logger) *AlertUsecase {
+	return &AlertUsecase{
+		repo:     repo,
+		channel:  channel,
+		notifier: notifier,
+		log:      log,
+	}
+}
+
+func (u *AlertUsecase) ListStockAlerts(ctx context.Context, sellerID int, query *dto.StockAlertQuery) ([]dto.StockAlertResponse, error) {
+	arg := repository.ListStockAlertsParams{
+		SellerID: sellerID,
+		Limit:    query.Limit,
+		Offset:   query.Offset,
+	}
+
+	alerts, err := u.repo.ListStockAlerts(ctx, arg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get alerts")
+	}
+
+	return converter.ToStockAlertResponses(alerts), nil
+}
+
+func (u *AlertUsecase) SetStockThreshold(ctx context.Context, productID, sellerID int, payload *dto.StockThresholdPayload) (*dto.StockThresholdResponse, error) {
+	arg := repository.UpdateStockThresholdParams{
+		ProductID:         productID,
+		SellerID:          sellerID,
+		LowStockThreshold: *payload.LowStockThreshold,
+	}
+
+	threshold, err := u.repo.UpdateStockThreshold(ctx, arg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update stock threshold")
+	}
+
+	response := converter.ToStockThresholdResponse(threshold)
+	return &response, nil
+}
+
+// DeliverPendingAlerts sends the alerts raised by payments and not delivered
+// yet, oldest first. A failed alert is retried on a later run until it runs
+// out of attempts. It returns how many alerts were delivered.
+func (u *AlertUsecase) DeliverPendingAlerts(ctx context.Context) (int, error) {
+	arg := repository.ListPendingStockAlertsParams{
+		MaxAttempts: alertMaxAttempts,
+		Limit:       alertDeliveryBatch,
+	}
+
+	alerts, err := u.repo.ListPendingStockAlerts(ctx, arg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get pending alerts")
+	}
+
+	delivered := 0
+	for _, alert := range alerts {
+		if err := u.deliver(ctx, alert); err != nil {
+			u.log.WithError(err).WithField("alert_id", alert.ID).Warn("failed to deliver stock alert")
+			if err := u.repo.RecordStockAlertAttempt(ctx, alert.ID); err != nil {
+				return delivered, errors.Wrap(err, "failed to record alert attempt")
+			}
+			continue
+		}
+		if err := u.repo.MarkStockAlertDelivered(ctx, alert.ID); err != nil {
+			return delivered, errors.Wrap(err, "failed to mark alert delivered")
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+func (u *AlertUsecase) deliver(ctx context.Context, alert model.StockAlertDelivery) error {
+	to := strconv.Itoa(alert.SellerID)
+	if u.channel == notifier.ChannelEmail {
+		if alert.SellerEmail == nil {
+			return errors.New("seller has no email")
+		}
+		to = *alert.SellerEmail
+	}
+
+	body := fmt.Sprintf("%s (SKU %s) is down to %d, at or below your threshold of %d.", alert.ProductName, alert.ProductSku, alert.Qty, alert.Threshold)
+	if alert.Qty <= 0 {
+		body = fmt.Sprintf("%s (SKU %s) is sold out.", alert.ProductName, alert.ProductSku)
+	}
+
+	message := notifier.Message{
+		Channel: u.channel,
+		To:      to,
+		Subject: fmt.Sprintf("Tutup Lapak low stock: %s", alert.ProductName),
+		Body:    body,
+	}
+	return u.notifier.Send(ctx, message)
+}
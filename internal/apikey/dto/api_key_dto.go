@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type APIKeyPayload struct {
+	Name   string   `json:"name" validate:"required,min=1,max=64"`
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=catalog:read product:write"`
+}
+
+type APIKeyResponse struct {
+	APIKeyID   string     `json:"apiKeyId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// APIKeyCreatedResponse is the only response that carries the full key.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyIdentity struct {
+	SellerID int
+	Scopes   []string
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"tutup-lapak/internal/apikey/dto"
+	"tutup-lapak/internal/apikey/usecase"
+	custom_middleware "tutup-lapak/internal/middleware"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type APIKeyHandler struct {
+	UseCase  *usecase.APIKeyUsecase
+	Validate *validator.Validate
+}
+
+func NewAPIKeyHandler(useCase *usecase.APIKeyUsecase, validate *validator.Validate) *APIKeyHandler {
+	return &APIKeyHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	apiKeys, err := h.UseCase.ListAPIKeys(ctx.Request().Context(), sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, apiKeys)
+}
+
+func (h *APIKeyHandler) CreateAPIKey(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.APIKeyPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	apiKey, err := h.UseCase.CreateAPIKey(ctx.Request().Context(), sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, apiKey)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("apiKeyId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	apiKey, err := h.UseCase.RevokeAPIKey(ctx.Request().Context(), id, sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, apiKey)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	ScopeCatalogRead  = "catalog:read"
+	ScopeProductWrite = "product:write"
+)
+
+type APIKey struct {
+	ID         int
+	SellerID   int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// HasScope reports whether scopes grant scope. Product write access implies
+// catalog read access.
+func HasScope(scopes []string, scope string) bool {
+	if slices.Contains(scopes, scope) {
+		return true
+	}
+	return scope == ScopeCatalogRead && slices.Contains(scopes, ScopeProductWrite)
+}
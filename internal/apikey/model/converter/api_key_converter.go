@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/apikey/dto"
+	"tutup-lapak/internal/apikey/model"
+)
+
+func ToAPIKeyResponse(apiKey model.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		APIKeyID:   strconv.Itoa(apiKey.ID),
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func ToAPIKeyResponses(apiKeys []model.APIKey) []dto.APIKeyResponse {
+	responses := make([]dto.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		responses = append(responses, ToAPIKeyResponse(apiKey))
+	}
+	return responses
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"tutup-lapak/internal/apikey/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+const createAPIKeyQuery = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (seller_id, name, prefix, key_hash, scopes) VALUES ($1, $2, $3, $4, $5)
+RETURNING id, seller_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at
+`
+
+type CreateAPIKeyParams struct {
+	SellerID int
+	Name     string
+	Prefix   string
+	KeyHash  string
+	Scopes   []string
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (model.APIKey, error) {
+	row := r.pool.QueryRow(ctx, createAPIKeyQuery,
+		arg.SellerID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+	)
+	return scanAPIKey(row)
+}
+
+const listAPIKeysQuery = `-- name: ListAPIKeys :many
+SELECT id, seller_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at FROM api_keys
+WHERE seller_id = $1
+ORDER BY created_at DESC
+`
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context, sellerID int) ([]model.APIKey, error) {
+	rows, err := r.pool.Query(ctx, listAPIKeysQuery, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.APIKey
+	for rows.Next() {
+		i, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveAPIKeyByPrefixQuery = `-- name: GetActiveAPIKeyByPrefix :one
+SELECT k.id, k.seller_id, k.name, k.prefix, k.key_hash, k.scopes, k.last_used_at, k.revoked_at, k.created_at
+FROM api_keys k
+JOIN sellers s ON s.id = k.seller_id
+WHERE k.prefix = $1 AND k.revoked_at IS NULL AND s.disabled_at IS NULL
+LIMIT 1
+`
+
+func (r *APIKeyRepository) GetActiveAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	row := r.pool.QueryRow(ctx, getActiveAPIKeyByPrefixQuery, prefix)
+	return scanAPIKey(row)
+}
+
+const touchAPIKeyQuery = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+// TouchAPIKey records usage at most once a minute to keep writes off the
+// hot path.
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, ID int) error {
+	_, err := r.pool.Exec(ctx, touchAPIKeyQuery, ID)
+	return err
+}
+
+const revokeAPIKeyQuery = `-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, NOW())
+WHERE id = $1 AND seller_id = $2
+RETURNING id, seller_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at
+`
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, ID, sellerID int) (model.APIKey, error) {
+	row := r.pool.QueryRow(ctx, revokeAPIKeyQuery, ID, sellerID)
+	return scanAPIKey(row)
+}
+
+func scanAPIKey(row pgx.Row) (model.APIKey, error) {
+	var i model.APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"tutup-lapak/internal/apikey/dto"
+	"tutup-lapak/internal/apikey/model/converter"
+	"tutup-lapak/internal/apikey/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/token"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// API keys look like tl_<prefix>_<secret>. The prefix is stored in clear
+	// for lookup, the full key only as a hash.
+	apiKeyScheme      = "tl_"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+type APIKeyUsecase struct {
+	repo *repository.APIKeyRepository
+}
+
+func NewAPIKeyUsecase(repo *repository.APIKeyRepository) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		repo: repo,
+	}
+}
+
+func (u *APIKeyUsecase) ListAPIKeys(ctx context.Context, sellerID int) ([]dto.APIKeyResponse, error) {
+	apiKeys, err := u.repo.ListAPIKeys(ctx, sellerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get api keys")
+	}
+
+	return converter.ToAPIKeyResponses(apiKeys), nil
+}
+
+func (u *APIKeyUsecase) CreateAPIKey(ctx context.Context, sellerID int, payload *dto.APIKeyPayload) (*dto.APIKeyCreatedResponse, error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to generate api key")
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+
+	secret, err := token.Generate(apiKeySecretBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate api key")
+	}
+	key := apiKeyScheme + prefix + "_" + secret
+
+	apiKey, err := u.repo.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		SellerID: sellerID,
+		Name:     payload.Name,
+		Prefix:   prefix,
+		KeyHash:  token.Hash(key),
+		Scopes:   payload.Scopes,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create api key")
+	}
+
+	return &dto.APIKeyCreatedResponse{
+		APIKeyResponse: converter.ToAPIKeyResponse(apiKey),
+		Key:            key,
+	}, nil
+}
+
+func (u *APIKeyUsecase) RevokeAPIKey(ctx context.Context, ID, sellerID int) (*dto.APIKeyResponse, error) {
+	apiKey, err := u.repo.RevokeAPIKey(ctx, ID, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "api key not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToAPIKeyResponse(apiKey)
+	return &response, nil
+}
+
+// Authenticate resolves a raw API key to the seller that owns it. Revoked
+// keys and keys of disabled sellers are rejected.
+func (u *APIKeyUsecase) Authenticate(ctx context.Context, key string) (*dto.APIKeyIdentity, error) {
+	rest, found := strings.CutPrefix(key, apiKeyScheme)
+	prefixLength := hex.EncodedLen(apiKeyPrefixBytes)
+	if !found || len(rest) <= prefixLength+1 || rest[prefixLength] != '_' {
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid api key")
+	}
+
+	apiKey, err := u.repo.GetActiveAPIKeyByPrefix(ctx, rest[:prefixLength])
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid api key")
+		}
+		return nil, errors.Wrap(err, "failed to get api key")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(token.Hash(key))) != 1 {
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid api key")
+	}
+
+	if err := u.repo.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to update api key usage")
+	}
+
+	return &dto.APIKeyIdentity{
+		SellerID: apiKey.SellerID,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package dto
+
+type RegisterRequest struct {
+	Email    string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" validate:"required_without=Email,omitempty,phone_number"`
+	Password string `json:"password" validate:"required,min=8,max=32"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" validate:"required_without=Email,omitempty,phone_number"`
+	Password string `json:"password" validate:"required,min=8,max=32"`
+}
+
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type AuthResponse struct {
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone string `json:"phone" validate:"required_without=Email,omitempty,phone_number"`
+}
+
+type ResetPasswordRequest struct {
+	Email    string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" validate:"required_without=Email,omitempty,phone_number"`
+	Code     string `json:"code" validate:"required,len=6,numeric"`
+	Password string `json:"password" validate:"required,min=8,max=32"`
+}
+
+type VerificationRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+}
+
+type VerificationConfirmRequest struct {
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+	Code    string `json:"code" validate:"required,len=6,numeric"`
+}
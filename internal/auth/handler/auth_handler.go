@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+
+	"tutup-lapak/internal/auth/dto"
+	"tutup-lapak/internal/auth/usecase"
+	custom_middleware "tutup-lapak/internal/middleware"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type AuthHandler struct {
+	UseCase  *usecase.AuthUsecase
+	Validate *validator.Validate
+}
+
+func NewAuthHandler(useCase *usecase.AuthUsecase, validate *validator.Validate) *AuthHandler {
+	return &AuthHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *AuthHandler) Register(ctx echo.Context) error {
+	var request = new(dto.RegisterRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	auth, err := h.UseCase.Register(ctx.Request().Context(), request)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, auth)
+}
+
+func (h *AuthHandler) Login(ctx echo.Context) error {
+	var request = new(dto.LoginRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	client := dto.ClientInfo{
+		IPAddress: ctx.RealIP(),
+		UserAgent: ctx.Request().UserAgent(),
+	}
+
+	auth, err := h.UseCase.Login(ctx.Request().Context(), request, client)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, auth)
+}
+
+func (h *AuthHandler) RefreshToken(ctx echo.Context) error {
+	var request = new(dto.RefreshTokenRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	tokens, err := h.UseCase.RefreshToken(ctx.Request().Context(), request)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(ctx echo.Context) error {
+	claim, err := custom_middleware.GetClaim(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.Logout(ctx.Request().Context(), claim.SessionID); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  http.StatusText(http.StatusOK),
+		Message: "Successfully logged out",
+	})
+}
+
+func (h *AuthHandler) JWKS(ctx echo.Context) error {
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return ctx.JSON(http.StatusOK, h.UseCase.JWKS())
+}
+
+func (h *AuthHandler) ForgotPassword(ctx echo.Context) error {
+	var request = new(dto.ForgotPasswordRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.ForgotPassword(ctx.Request().Context(), request); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  http.StatusText(http.StatusOK),
+		Message: "If the account exists, a reset code has been sent",
+	})
+}
+
+func (h *AuthHandler) ResetPassword(ctx echo.Context) error {
+	var request = new(dto.ResetPasswordRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.ResetPassword(ctx.Request().Context(), request); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  http.StatusText(http.StatusOK),
+		Message: "Password has been reset",
+	})
+}
+
+func (h *AuthHandler) RequestVerification(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var request = new(dto.VerificationRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.RequestVerification(ctx.Request().Context(), sellerID, request); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  http.StatusText(http.StatusOK),
+		Message: "Verification code has been sent",
+	})
+}
+
+func (h *AuthHandler) ConfirmVerification(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var request = new(dto.VerificationConfirmRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.ConfirmVerification(ctx.Request().Context(), sellerID, request); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  http.StatusText(http.StatusOK),
+		Message: "Contact has been verified",
+	})
+}
@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"tutup-lapak/internal/auth/dto"
+	"tutup-lapak/internal/auth/model"
+	"tutup-lapak/pkg/helper"
+)
+
+func ToAuthResponse(seller model.Seller, tokens dto.TokenResponse) dto.AuthResponse {
+	return dto.AuthResponse{
+		Email:        helper.DerefString(seller.Email, ""),
+		Phone:        helper.DerefString(seller.PhoneNumber, ""),
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+	}
+}
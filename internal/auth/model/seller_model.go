@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	RoleSeller  = "seller"
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+)
+
+// SellerConstraints maps the unique constraints of sellers to the conflict
+// reported for them, for every repository writing sellers.
+var SellerConstraints = map[string]string{
+	"sellers_email_key":        "email already exists",
+	"sellers_phone_number_key": "phone already exists",
+}
+
+type Seller struct {
+	ID             int
+	Email          *string
+	PhoneNumber    *string
+	HashedPassword string
+	Role           string
+	DisabledAt     *time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type Session struct {
+	ID        string
+	SellerID  int
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+type RefreshToken struct {
+	ID        int
+	SessionID string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
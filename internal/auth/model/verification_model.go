@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const (
+	PurposePasswordReset = "password_reset"
+	PurposeVerifyEmail   = "verify_email"
+	PurposeVerifyPhone   = "verify_phone"
+)
+
+type VerificationCode struct {
+	ID         int
+	SellerID   int
+	Purpose    string
+	Target     string
+	CodeHash   string
+	Attempts   int
+	ExpiresAt  time.Time
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"tutup-lapak/internal/auth/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuthRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthRepository(pool *pgxpool.Pool) *AuthRepository {
+	return &AuthRepository{pool: pool}
+}
+
+const createSellerQuery = `-- name: CreateSeller :one
+INSERT INTO sellers (email, phone_number, hashed_password) VALUES ($1, $2, $3)
+RETURNING id, email, phone_number, hashed_password, role, disabled_at
+`
+
+type CreateSellerParams struct {
+	Email          *string
+	PhoneNumber    *string
+	HashedPassword string
+}
+
+func (r *AuthRepository) CreateSeller(ctx context.Context, arg CreateSellerParams) (model.Seller, error) {
+	row := r.pool.QueryRow(ctx, createSellerQuery, arg.Email, arg.PhoneNumber, arg.HashedPassword)
+
+	var seller model.Seller
+	err := row.Scan(
+		&seller.ID,
+		&seller.Email,
+		&seller.PhoneNumber,
+		&seller.HashedPassword,
+		&seller.Role,
+		&seller.DisabledAt,
+	)
+	if err != nil {
+		return model.Seller{}, customErrors.HandlePgConstraintError(err, "failed to create seller", model.SellerConstraints)
+	}
+	return seller, nil
+}
+
+const getSellerByEmailQuery = `-- name: GetSellerByEmail :one
+SELECT id, email, phone_number, hashed_password, role, disabled_at FROM sellers
+WHERE email = $1
+LIMIT 1
+`
+
+func (r *AuthRepository) GetSellerByEmail(ctx context.Context, email string) (model.Seller, error) {
+	row := r.pool.QueryRow(ctx, getSellerByEmailQuery, email)
+
+	var seller model.Seller
+	err := row.Scan(
+		&seller.ID,
+		&seller.Email,
+		&seller.PhoneNumber,
+		&seller.HashedPassword,
+		&seller.Role,
+		&seller.DisabledAt,
+	)
+	return seller, err
+}
+
+const getSellerByPhoneQuery = `-- name: GetSellerByPhone :one
+SELECT id, email, phone_number, hashed_password, role, disabled_at FROM sellers
+WHERE phone_number = $1
+LIMIT 1
+`
+
+func (r *AuthRepository) GetSellerByPhone(ctx context.Context, phone string) (model.Seller, error) {
+	row := r.pool.QueryRow(ctx, getSellerByPhoneQuery, phone)
+
+	var seller model.Seller
+	err := row.Scan(
+		&seller.ID,
+		&seller.Email,
+		&seller.PhoneNumber,
+		&seller.HashedPassword,
+		&seller.Role,
+		&seller.DisabledAt,
+	)
+	return seller, err
+}
+
+const getSellerByIDQuery = `-- name: GetSellerByID :one
+SELECT id, email, phone_number, hashed_password, role, disabled_at FROM sellers
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *AuthRepository) GetSellerByID(ctx context.Context, sellerID int) (model.Seller, error) {
+	row := r.pool.QueryRow(ctx, getSellerByIDQuery, sellerID)
+
+	var seller model.Seller
+	err := row.Scan(
+		&seller.ID,
+		&seller.Email,
+		&seller.PhoneNumber,
+		&seller.HashedPassword,
+		&seller.Role,
+		&seller.DisabledAt,
+	)
+	return seller, err
+}
+
+const createSessionQuery = `-- name: CreateSession :exec
+INSERT INTO sessions (id, seller_id) VALUES ($1, $2)
+`
+
+const createRefreshTokenQuery = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)
+`
+
+type CreateSessionParams struct {
+	ID        string
+	SellerID  int
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+func (r *AuthRepository) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, createSessionQuery, arg.ID, arg.SellerID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, createRefreshTokenQuery, arg.ID, arg.TokenHash, arg.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+const markRefreshTokenUsedQuery = `-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL
+`
+
+type RotateRefreshTokenParams struct {
+	SessionID    string
+	TokenHash    string
+	NewTokenHash string
+	ExpiresAt    time.Time
+}
+
+// RotateRefreshToken consumes an unused refresh token and issues its
+// successor in one transaction. It returns ErrNotFound when the token was
+// already used, including by a concurrent rotation.
+func (r *AuthRepository) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, markRefreshTokenUsedQuery, arg.TokenHash)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() != 1 {
+		return customErrors.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, createRefreshTokenQuery, arg.SessionID, arg.NewTokenHash, arg.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+const getRefreshTokenByHashQuery = `-- name: GetRefreshTokenByHash :one
+SELECT id, session_id, token_hash, expires_at, used_at, created_at FROM refresh_tokens
+WHERE token_hash = $1
+LIMIT 1
+`
+
+func (r *AuthRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (model.RefreshToken, error) {
+	row := r.pool.QueryRow(ctx, getRefreshTokenByHashQuery, tokenHash)
+
+	var i model.RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSessionQuery = `-- name: GetSession :one
+SELECT id, seller_id, created_at, revoked_at FROM sessions
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *AuthRepository) GetSession(ctx context.Context, sessionID string) (model.Session, error) {
+	row := r.pool.QueryRow(ctx, getSessionQuery, sessionID)
+
+	var i model.Session
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const revokeSessionQuery = `-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (r *AuthRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	_, err := r.pool.Exec(ctx, revokeSessionQuery, sessionID)
+	return err
+}
+
+const consumeVerificationCodesQuery = `-- name: ConsumeVerificationCodes :exec
+UPDATE verification_codes
+SET consumed_at = NOW()
+WHERE seller_id = $1 AND purpose = $2 AND consumed_at IS NULL
+`
+
+const createVerificationCodeQuery = `-- name: CreateVerificationCode :exec
+INSERT INTO verification_codes (seller_id, purpose, target, code_hash, expires_at) VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateVerificationCodeParams struct {
+	SellerID  int
+	Purpose   string
+	Target    string
+	CodeHash  string
+	ExpiresAt time.Time
+}
+
+// CreateVerificationCode stores a new code and invalidates every code
+// previously issued for the same purpose.
+func (r *AuthRepository) CreateVerificationCode(ctx context.Context, arg CreateVerificationCodeParams) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, consumeVerificationCodesQuery, arg.SellerID, arg.Purpose); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, createVerificationCodeQuery,
+		arg.SellerID,
+		arg.Purpose,
+		arg.Target,
+		arg.CodeHash,
+		arg.ExpiresAt,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+const attemptVerificationCodeQuery = `-- name: AttemptVerificationCode :one
+UPDATE verification_codes
+SET attempts = attempts + 1
+WHERE id = (
+	SELECT id FROM verification_codes
+	WHERE seller_id = $1 AND purpose = $2 AND consumed_at IS NULL AND expires_at > NOW()
+	ORDER BY created_at DESC
+	LIMIT 1
+) AND attempts < $3
+RETURNING id, seller_id, purpose, target, code_hash, attempts, expires_at, consumed_at, created_at
+`
+
+// AttemptVerificationCode counts an attempt against the latest active code
+// and returns it. It returns ErrNotFound when there is no active code or its
+// attempts are exhausted.
+func (r *AuthRepository) AttemptVerificationCode(ctx context.Context, sellerID int, purpose string, maxAttempts int) (model.VerificationCode, error) {
+	row := r.pool.QueryRow(ctx, attemptVerificationCodeQuery, sellerID, purpose, maxAttempts)
+
+	var i model.VerificationCode
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Purpose,
+		&i.Target,
+		&i.CodeHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const consumeVerificationCodeQuery = `-- name: ConsumeVerificationCode :execrows
+UPDATE verification_codes
+SET consumed_at = NOW()
+WHERE id = $1 AND consumed_at IS NULL
+`
+
+func (r *AuthRepository) ConsumeVerificationCode(ctx context.Context, codeID int) (bool, error) {
+	result, err := r.pool.Exec(ctx, consumeVerificationCodeQuery, codeID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+const updatePasswordQuery = `-- name: UpdatePassword :exec
+UPDATE sellers SET hashed_password = $2 WHERE id = $1
+`
+
+const revokeSellerSessionsQuery = `-- name: RevokeSellerSessions :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE seller_id = $1 AND revoked_at IS NULL
+`
+
+// UpdatePassword replaces the password and revokes every session, so a reset
+// also logs out whoever knew the old password.
+func (r *AuthRepository) UpdatePassword(ctx context.Context, sellerID int, hashedPassword string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, updatePasswordQuery, sellerID, hashedPassword); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, revokeSellerSessionsQuery, sellerID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+const markEmailVerifiedQuery = `-- name: MarkEmailVerified :execrows
+UPDATE sellers SET email_verified_at = NOW() WHERE id = $1 AND email = $2
+`
+
+const markPhoneVerifiedQuery = `-- name: MarkPhoneVerified :execrows
+UPDATE sellers SET phone_verified_at = NOW() WHERE id = $1 AND phone_number = $2
+`
+
+// MarkContactVerified marks the email or phone as verified, provided it still
+// matches the target the code was sent to.
+func (r *AuthRepository) MarkContactVerified(ctx context.Context, sellerID int, purpose, target string) (bool, error) {
+	query := markEmailVerifiedQuery
+	if purpose == model.PurposeVerifyPhone {
+		query = markPhoneVerifiedQuery
+	}
+
+	result, err := r.pool.Exec(ctx, query, sellerID, target)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
@@ -0,0 +1,453 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"tutup-lapak/internal/auth/dto"
+	"tutup-lapak/internal/auth/model"
+	"tutup-lapak/internal/auth/model/converter"
+	"tutup-lapak/internal/auth/repository"
+	securityModel "tutup-lapak/internal/security/model"
+	securityUsecase "tutup-lapak/internal/security/usecase"
+	"tutup-lapak/pkg/bycript"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/helper"
+	"tutup-lapak/pkg/jwt"
+	"tutup-lapak/pkg/notifier"
+	"tutup-lapak/pkg/token"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	accessTokenTTL   = 15 * time.Minute
+	refreshTokenTTL  = 30 * 24 * time.Hour
+	refreshTokenSize = 32
+
+	verificationCodeTTL         = 15 * time.Minute
+	verificationCodeDigits      = 6
+	verificationCodeMaxAttempts = 5
+
+	// dummyPasswordHash is compared against when the account does not exist,
+	// so that answer takes as long as a wrong password
+	dummyPasswordHash = "$2a$10$LjBgXtI/Snjt2IbGm1.cgOBKvrDJvkGrbBhhA32olVfxvSXQp4j/e"
+)
+
+type AuthUsecase struct {
+	authRepo        *repository.AuthRepository
+	jwtKeys         *jwt.KeySet
+	notifier        notifier.Notifier
+	securityUsecase *securityUsecase.SecurityUsecase
+}
+
+func NewAuthUsecase(authRepo *repository.AuthRepository, jwtKeys *jwt.KeySet, notifier notifier.Notifier, securityUsecase *securityUsecase.SecurityUsecase) *AuthUsecase {
+	return &AuthUsecase{
+		authRepo:        authRepo,
+		jwtKeys:         jwtKeys,
+		notifier:        notifier,
+		securityUsecase: securityUsecase,
+	}
+}
+
+func (u *AuthUsecase) Register(ctx context.Context, request *dto.RegisterRequest) (*dto.AuthResponse, error) {
+	hashedPassword, err := bycript.HashPassword(request.Password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash password")
+	}
+
+	arg := repository.CreateSellerParams{
+		Email:          helper.NilIfEmpty(normalizeEmail(request.Email)),
+		PhoneNumber:    helper.NilIfEmpty(request.Phone),
+		HashedPassword: hashedPassword,
+	}
+
+	seller, err := u.authRepo.CreateSeller(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.startSession(ctx, seller)
+}
+
+// Login counts failures per account and per client IP, and refuses to check
+// the password at all while either of them is locked out.
+func (u *AuthUsecase) Login(ctx context.Context, request *dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	identifier := request.Phone
+	if request.Email != "" {
+		identifier = normalizeEmail(request.Email)
+	}
+	// the identifier is user supplied and unbounded, hashing keeps the key
+	// within the column
+	accountKey := "login:account:" + token.Hash(identifier)
+	clientKey := "login:ip:" + client.IPAddress
+
+	if err := u.securityUsecase.Check(ctx, accountKey, clientKey); err != nil {
+		if recordErr := u.recordLoginEvent(ctx, nil, securityModel.EventLoginLocked, identifier, client); recordErr != nil {
+			return nil, recordErr
+		}
+		return nil, err
+	}
+
+	seller, err := u.getSellerByContact(ctx, request.Email, request.Phone)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			_ = bycript.ComparePassword(request.Password, dummyPasswordHash)
+			if failErr := u.loginFailed(ctx, nil, identifier, accountKey, clientKey, client); failErr != nil {
+				return nil, failErr
+			}
+			return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid credentials")
+		}
+		return nil, errors.Wrap(err, "failed to get seller")
+	}
+
+	if err := bycript.ComparePassword(request.Password, seller.HashedPassword); err != nil {
+		if failErr := u.loginFailed(ctx, &seller.ID, identifier, accountKey, clientKey, client); failErr != nil {
+			return nil, failErr
+		}
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid credentials")
+	}
+
+	if seller.DisabledAt != nil {
+		if err := u.recordLoginEvent(ctx, &seller.ID, securityModel.EventLoginBlocked, identifier, client); err != nil {
+			return nil, err
+		}
+		return nil, errors.Wrap(customErrors.ErrForbidden, "account is disabled")
+	}
+
+	if err := u.securityUsecase.Reset(ctx, accountKey); err != nil {
+		return nil, err
+	}
+	if err := u.recordLoginEvent(ctx, &seller.ID, securityModel.EventLoginSucceeded, identifier, client); err != nil {
+		return nil, err
+	}
+
+	return u.startSession(ctx, seller)
+}
+
+// RefreshToken rotates a refresh token. Presenting a token that was already
+// rotated is treated as theft and revokes the whole session.
+func (u *AuthUsecase) RefreshToken(ctx context.Context, request *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	tokenHash := token.Hash(request.RefreshToken)
+
+	refreshToken, err := u.authRepo.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrUnauthorized, "invalid refresh token")
+		}
+		return nil, errors.Wrap(err, "failed to get refresh token")
+	}
+	if refreshToken.UsedAt != nil {
+		return nil, u.revokeReusedSession(ctx, refreshToken.SessionID)
+	}
+
+	if time.Now().After(refreshToken.ExpiresAt) {
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "refresh token expired")
+	}
+
+	session, err := u.authRepo.GetSession(ctx, refreshToken.SessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "session revoked")
+	}
+
+	seller, err := u.authRepo.GetSellerByID(ctx, session.SellerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get seller")
+	}
+	if seller.DisabledAt != nil {
+		return nil, errors.Wrap(customErrors.ErrForbidden, "account is disabled")
+	}
+
+	newRefreshToken, err := token.Generate(refreshTokenSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate refresh token")
+	}
+
+	arg := repository.RotateRefreshTokenParams{
+		SessionID:    session.ID,
+		TokenHash:    tokenHash,
+		NewTokenHash: token.Hash(newRefreshToken),
+		ExpiresAt:    time.Now().Add(refreshTokenTTL),
+	}
+	if err := u.authRepo.RotateRefreshToken(ctx, arg); err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			// a concurrent request rotated the same token first
+			return nil, u.revokeReusedSession(ctx, session.ID)
+		}
+		return nil, errors.Wrap(err, "failed to rotate refresh token")
+	}
+
+	accessToken, err := u.jwtKeys.CreateToken(seller.ID, seller.Role, session.ID, accessTokenTTL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create token")
+	}
+
+	return &dto.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// revokeReusedSession revokes the session of a refresh token presented after
+// it was rotated.
+func (u *AuthUsecase) revokeReusedSession(ctx context.Context, sessionID string) error {
+	if err := u.authRepo.RevokeSession(ctx, sessionID); err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	return errors.Wrap(customErrors.ErrUnauthorized, "refresh token reused, session revoked")
+}
+
+func (u *AuthUsecase) Logout(ctx context.Context, sessionID string) error {
+	if err := u.authRepo.RevokeSession(ctx, sessionID); err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	return nil
+}
+
+// IsSessionActive reports whether access tokens issued for the session are
+// still accepted.
+func (u *AuthUsecase) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+
+	session, err := u.authRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to get session")
+	}
+
+	return session.RevokedAt == nil, nil
+}
+
+// ForgotPassword sends a reset code to the given email or phone. Unknown
+// contacts succeed silently so the endpoint can't be used to probe accounts,
+// and sends are counted per identifier before the lookup for the same reason.
+func (u *AuthUsecase) ForgotPassword(ctx context.Context, request *dto.ForgotPasswordRequest) error {
+	identifier := request.Phone
+	if request.Email != "" {
+		identifier = normalizeEmail(request.Email)
+	}
+	sendKey := "password-forgot:account:" + token.Hash(identifier)
+
+	if err := u.securityUsecase.Check(ctx, sendKey); err != nil {
+		return err
+	}
+	if err := u.securityUsecase.RegisterFailure(ctx, sendKey, securityUsecase.SendPolicy); err != nil {
+		return err
+	}
+
+	seller, err := u.getSellerByContact(ctx, request.Email, request.Phone)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get seller")
+	}
+
+	channel, target := notifier.ChannelEmail, normalizeEmail(request.Email)
+	if request.Email == "" {
+		channel, target = notifier.ChannelPhone, request.Phone
+	}
+
+	return u.sendVerificationCode(ctx, seller.ID, model.PurposePasswordReset, channel, target)
+}
+
+func (u *AuthUsecase) ResetPassword(ctx context.Context, request *dto.ResetPasswordRequest) error {
+	seller, err := u.getSellerByContact(ctx, request.Email, request.Phone)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return errors.Wrap(customErrors.ErrBadRequest, "invalid or expired code")
+		}
+		return errors.Wrap(err, "failed to get seller")
+	}
+
+	if _, err := u.checkVerificationCode(ctx, seller.ID, model.PurposePasswordReset, request.Code); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bycript.HashPassword(request.Password)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash password")
+	}
+
+	if err := u.authRepo.UpdatePassword(ctx, seller.ID, hashedPassword); err != nil {
+		return errors.Wrap(err, "failed to update password")
+	}
+	return nil
+}
+
+func (u *AuthUsecase) RequestVerification(ctx context.Context, sellerID int, request *dto.VerificationRequest) error {
+	seller, err := u.authRepo.GetSellerByID(ctx, sellerID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get seller")
+	}
+
+	purpose, target := model.PurposeVerifyEmail, seller.Email
+	if request.Channel == notifier.ChannelPhone {
+		purpose, target = model.PurposeVerifyPhone, seller.PhoneNumber
+	}
+	if target == nil {
+		return errors.Wrapf(customErrors.ErrBadRequest, "%s is not set", request.Channel)
+	}
+
+	return u.sendVerificationCode(ctx, seller.ID, purpose, request.Channel, *target)
+}
+
+func (u *AuthUsecase) ConfirmVerification(ctx context.Context, sellerID int, request *dto.VerificationConfirmRequest) error {
+	purpose := model.PurposeVerifyEmail
+	if request.Channel == notifier.ChannelPhone {
+		purpose = model.PurposeVerifyPhone
+	}
+
+	code, err := u.checkVerificationCode(ctx, sellerID, purpose, request.Code)
+	if err != nil {
+		return err
+	}
+
+	verified, err := u.authRepo.MarkContactVerified(ctx, sellerID, purpose, code.Target)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify contact")
+	}
+	if !verified {
+		return errors.Wrapf(customErrors.ErrBadRequest, "%s changed since the code was sent", request.Channel)
+	}
+	return nil
+}
+
+func (u *AuthUsecase) sendVerificationCode(ctx context.Context, sellerID int, purpose, channel, target string) error {
+	code, err := token.GenerateNumeric(verificationCodeDigits)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate code")
+	}
+
+	arg := repository.CreateVerificationCodeParams{
+		SellerID:  sellerID,
+		Purpose:   purpose,
+		Target:    target,
+		CodeHash:  token.Hash(code),
+		ExpiresAt: time.Now().Add(verificationCodeTTL),
+	}
+	if err := u.authRepo.CreateVerificationCode(ctx, arg); err != nil {
+		return errors.Wrap(err, "failed to create code")
+	}
+
+	subject := "Tutup Lapak verification code"
+	if purpose == model.PurposePasswordReset {
+		subject = "Tutup Lapak password reset code"
+	}
+
+	message := notifier.Message{
+		Channel: channel,
+		To:      target,
+		Subject: subject,
+		Body:    fmt.Sprintf("Your code is %s. It expires in %d minutes.", code, int(verificationCodeTTL.Minutes())),
+	}
+	if err := u.notifier.Send(ctx, message); err != nil {
+		return errors.Wrap(err, "failed to send code")
+	}
+	return nil
+}
+
+// checkVerificationCode consumes the latest active code when it matches.
+// Every check counts as an attempt, and a code is dead once its attempts are
+// exhausted.
+func (u *AuthUsecase) checkVerificationCode(ctx context.Context, sellerID int, purpose, code string) (model.VerificationCode, error) {
+	invalidCodeErr := errors.Wrap(customErrors.ErrBadRequest, "invalid or expired code")
+
+	verificationCode, err := u.authRepo.AttemptVerificationCode(ctx, sellerID, purpose, verificationCodeMaxAttempts)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return model.VerificationCode{}, invalidCodeErr
+		}
+		return model.VerificationCode{}, errors.Wrap(err, "failed to check code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token.Hash(code)), []byte(verificationCode.CodeHash)) != 1 {
+		return model.VerificationCode{}, invalidCodeErr
+	}
+
+	consumed, err := u.authRepo.ConsumeVerificationCode(ctx, verificationCode.ID)
+	if err != nil {
+		return model.VerificationCode{}, errors.Wrap(err, "failed to consume code")
+	}
+	if !consumed {
+		return model.VerificationCode{}, invalidCodeErr
+	}
+
+	return verificationCode, nil
+}
+
+func (u *AuthUsecase) loginFailed(ctx context.Context, sellerID *int, identifier, accountKey, clientKey string, client dto.ClientInfo) error {
+	if err := u.securityUsecase.RegisterFailure(ctx, accountKey, securityUsecase.AccountPolicy); err != nil {
+		return err
+	}
+	if err := u.securityUsecase.RegisterFailure(ctx, clientKey, securityUsecase.ClientPolicy); err != nil {
+		return err
+	}
+	return u.recordLoginEvent(ctx, sellerID, securityModel.EventLoginFailed, identifier, client)
+}
+
+func (u *AuthUsecase) recordLoginEvent(ctx context.Context, sellerID *int, eventType, identifier string, client dto.ClientInfo) error {
+	return u.securityUsecase.RecordEvent(ctx, securityModel.SecurityEvent{
+		SellerID:   sellerID,
+		EventType:  eventType,
+		Identifier: identifier,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+	})
+}
+
+func (u *AuthUsecase) getSellerByContact(ctx context.Context, email, phone string) (model.Seller, error) {
+	if email != "" {
+		return u.authRepo.GetSellerByEmail(ctx, normalizeEmail(email))
+	}
+	return u.authRepo.GetSellerByPhone(ctx, phone)
+}
+
+func (u *AuthUsecase) startSession(ctx context.Context, seller model.Seller) (*dto.AuthResponse, error) {
+	sessionID := uuid.New().String()
+
+	refreshToken, err := token.Generate(refreshTokenSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate refresh token")
+	}
+
+	arg := repository.CreateSessionParams{
+		ID:        sessionID,
+		SellerID:  seller.ID,
+		TokenHash: token.Hash(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := u.authRepo.CreateSession(ctx, arg); err != nil {
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+
+	accessToken, err := u.jwtKeys.CreateToken(seller.ID, seller.Role, sessionID, accessTokenTTL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create token")
+	}
+
+	response := converter.ToAuthResponse(seller, dto.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	})
+	return &response, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (u *AuthUsecase) JWKS() jwt.JWKSet {
+	return u.jwtKeys.JWKS()
+}
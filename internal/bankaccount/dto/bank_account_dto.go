@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type BankAccountPayload struct {
+	BankAccountName   string `json:"bankAccountName" validate:"required,min=4,max=32"`
+	BankAccountHolder string `json:"bankAccountHolder" validate:"required,min=4,max=32"`
+	BankAccountNumber string `json:"bankAccountNumber" validate:"required,min=4,max=32"`
+	IsDefault         bool   `json:"isDefault"`
+}
+
+type BankAccountUpdatePayload struct {
+	BankAccountName   *string `json:"bankAccountName" validate:"omitnil,min=4,max=32"`
+	BankAccountHolder *string `json:"bankAccountHolder" validate:"omitnil,min=4,max=32"`
+	BankAccountNumber *string `json:"bankAccountNumber" validate:"omitnil,min=4,max=32"`
+	IsDefault         *bool   `json:"isDefault"`
+}
+
+type BankAccountResponse struct {
+	BankAccountID     string    `json:"bankAccountId"`
+	BankAccountName   string    `json:"bankAccountName"`
+	BankAccountHolder string    `json:"bankAccountHolder"`
+	BankAccountNumber string    `json:"bankAccountNumber"`
+	IsDefault         bool      `json:"isDefault"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"tutup-lapak/internal/bankaccount/dto"
+	"tutup-lapak/internal/bankaccount/usecase"
+	custom_middleware "tutup-lapak/internal/middleware"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type BankAccountHandler struct {
+	UseCase  *usecase.BankAccountUsecase
+	Validate *validator.Validate
+}
+
+func NewBankAccountHandler(useCase *usecase.BankAccountUsecase, validate *validator.Validate) *BankAccountHandler {
+	return &BankAccountHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *BankAccountHandler) ListBankAccounts(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	accounts, err := h.UseCase.ListBankAccounts(ctx.Request().Context(), sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, accounts)
+}
+
+func (h *BankAccountHandler) CreateBankAccount(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.BankAccountPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	account, err := h.UseCase.CreateBankAccount(ctx.Request().Context(), sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, account)
+}
+
+func (h *BankAccountHandler) UpdateBankAccount(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("bankAccountId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.BankAccountUpdatePayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	account, err := h.UseCase.UpdateBankAccount(ctx.Request().Context(), id, sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) DeleteBankAccount(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("bankAccountId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.DeleteBankAccount(ctx.Request().Context(), id, sellerID); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  "OK",
+		Message: "Bank account is deleted",
+	})
+}
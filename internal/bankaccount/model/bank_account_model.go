@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type BankAccount struct {
+	ID                int
+	SellerID          int
+	BankAccountName   string
+	BankAccountHolder string
+	BankAccountNumber string
+	IsDefault         bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
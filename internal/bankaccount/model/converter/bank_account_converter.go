@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/bankaccount/dto"
+	"tutup-lapak/internal/bankaccount/model"
+)
+
+func ToBankAccountResponse(account model.BankAccount) dto.BankAccountResponse {
+	return dto.BankAccountResponse{
+		BankAccountID:     strconv.Itoa(account.ID),
+		BankAccountName:   account.BankAccountName,
+		BankAccountHolder: account.BankAccountHolder,
+		BankAccountNumber: account.BankAccountNumber,
+		IsDefault:         account.IsDefault,
+		CreatedAt:         account.CreatedAt,
+		UpdatedAt:         account.UpdatedAt,
+	}
+}
+
+func ToBankAccountResponses(accounts []model.BankAccount) []dto.BankAccountResponse {
+	responses := make([]dto.BankAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		responses = append(responses, ToBankAccountResponse(account))
+	}
+	return responses
+}
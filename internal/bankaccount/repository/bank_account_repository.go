@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+
+	"tutup-lapak/internal/bankaccount/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BankAccountRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBankAccountRepository(pool *pgxpool.Pool) *BankAccountRepository {
+	return &BankAccountRepository{pool: pool}
+}
+
+var bankAccountConstraints = map[string]string{
+	"idx_seller_bank_accounts_default": "default bank account was changed concurrently",
+}
+
+const listBankAccountsQuery = `-- name: ListBankAccounts :many
+SELECT id, seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default, created_at, updated_at
+FROM seller_bank_accounts
+WHERE seller_id = $1
+ORDER BY is_default DESC, created_at ASC
+`
+
+func (r *BankAccountRepository) ListBankAccounts(ctx context.Context, sellerID int) ([]model.BankAccount, error) {
+	rows, err := r.pool.Query(ctx, listBankAccountsQuery, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.BankAccount
+	for rows.Next() {
+		i, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countBankAccountsQuery = `-- name: CountBankAccounts :one
+SELECT COUNT(*) FROM seller_bank_accounts WHERE seller_id = $1
+`
+
+const clearDefaultBankAccountQuery = `-- name: ClearDefaultBankAccount :exec
+UPDATE seller_bank_accounts SET is_default = FALSE WHERE seller_id = $1 AND is_default
+`
+
+const createBankAccountQuery = `-- name: CreateBankAccount :one
+INSERT INTO seller_bank_accounts (
+  seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default, created_at, updated_at
+`
+
+type CreateBankAccountParams struct {
+	SellerID          int
+	BankAccountName   string
+	BankAccountHolder string
+	BankAccountNumber string
+	IsDefault         bool
+}
+
+// CreateBankAccount inserts a bank account. The first account of a seller
+// always becomes the default one.
+func (r *BankAccountRepository) CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (model.BankAccount, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return model.BankAccount{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var count int
+	if err := tx.QueryRow(ctx, countBankAccountsQuery, arg.SellerID).Scan(&count); err != nil {
+		return model.BankAccount{}, err
+	}
+
+	isDefault := arg.IsDefault || count == 0
+	if isDefault {
+		if _, err := tx.Exec(ctx, clearDefaultBankAccountQuery, arg.SellerID); err != nil {
+			return model.BankAccount{}, err
+		}
+	}
+
+	row := tx.QueryRow(ctx, createBankAccountQuery,
+		arg.SellerID,
+		arg.BankAccountName,
+		arg.BankAccountHolder,
+		arg.BankAccountNumber,
+		isDefault,
+	)
+	account, err := scanBankAccount(row)
+	if err != nil {
+		return model.BankAccount{}, customErrors.HandlePgConstraintError(err, "failed to create bank account", bankAccountConstraints)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BankAccount{}, customErrors.HandlePgConstraintError(err, "failed to create bank account", bankAccountConstraints)
+	}
+
+	return account, nil
+}
+
+const updateBankAccountQuery = `-- name: UpdateBankAccount :one
+UPDATE seller_bank_accounts
+SET
+	bank_account_name = COALESCE($3, bank_account_name),
+	bank_account_holder = COALESCE($4, bank_account_holder),
+	bank_account_number = COALESCE($5, bank_account_number),
+	is_default = is_default OR $6
+WHERE id = $1 AND seller_id = $2
+RETURNING id, seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default, created_at, updated_at
+`
+
+type UpdateBankAccountParams struct {
+	ID                int
+	SellerID          int
+	BankAccountName   *string
+	BankAccountHolder *string
+	BankAccountNumber *string
+	MakeDefault       bool
+}
+
+func (r *BankAccountRepository) UpdateBankAccount(ctx context.Context, arg UpdateBankAccountParams) (model.BankAccount, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return model.BankAccount{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if arg.MakeDefault {
+		if _, err := tx.Exec(ctx, clearDefaultBankAccountQuery, arg.SellerID); err != nil {
+			return model.BankAccount{}, err
+		}
+	}
+
+	row := tx.QueryRow(ctx, updateBankAccountQuery,
+		arg.ID,
+		arg.SellerID,
+		arg.BankAccountName,
+		arg.BankAccountHolder,
+		arg.BankAccountNumber,
+		arg.MakeDefault,
+	)
+	account, err := scanBankAccount(row)
+	if err != nil {
+		return model.BankAccount{}, customErrors.HandlePgConstraintError(err, "failed to update bank account", bankAccountConstraints)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BankAccount{}, customErrors.HandlePgConstraintError(err, "failed to update bank account", bankAccountConstraints)
+	}
+
+	return account, nil
+}
+
+const deleteBankAccountQuery = `-- name: DeleteBankAccount :one
+DELETE FROM seller_bank_accounts
+WHERE id = $1 AND seller_id = $2
+RETURNING is_default
+`
+
+const promoteDefaultBankAccountQuery = `-- name: PromoteDefaultBankAccount :exec
+UPDATE seller_bank_accounts
+SET is_default = TRUE
+WHERE id = (
+	SELECT id FROM seller_bank_accounts
+	WHERE seller_id = $1
+	ORDER BY created_at ASC
+	LIMIT 1
+)
+`
+
+// DeleteBankAccount removes a bank account. When the default account is
+// removed the oldest remaining account becomes the default.
+func (r *BankAccountRepository) DeleteBankAccount(ctx context.Context, ID, sellerID int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var wasDefault bool
+	if err := tx.QueryRow(ctx, deleteBankAccountQuery, ID, sellerID).Scan(&wasDefault); err != nil {
+		return customErrors.HandlePgError(err, "failed to delete bank account")
+	}
+
+	if wasDefault {
+		if _, err := tx.Exec(ctx, promoteDefaultBankAccountQuery, sellerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanBankAccount(row pgx.Row) (model.BankAccount, error) {
+	var i model.BankAccount
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.BankAccountName,
+		&i.BankAccountHolder,
+		&i.BankAccountNumber,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+
+	"tutup-lapak/internal/bankaccount/dto"
+	"tutup-lapak/internal/bankaccount/model/converter"
+	"tutup-lapak/internal/bankaccount/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+type BankAccountUsecase struct {
+	repo *repository.BankAccountRepository
+}
+
+func NewBankAccountUsecase(repo *repository.BankAccountRepository) *BankAccountUsecase {
+	return &BankAccountUsecase{
+		repo: repo,
+	}
+}
+
+func (u *BankAccountUsecase) ListBankAccounts(ctx context.Context, sellerID int) ([]dto.BankAccountResponse, error) {
+	accounts, err := u.repo.ListBankAccounts(ctx, sellerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get bank accounts")
+	}
+
+	return converter.ToBankAccountResponses(accounts), nil
+}
+
+func (u *BankAccountUsecase) CreateBankAccount(ctx context.Context, sellerID int, payload *dto.BankAccountPayload) (*dto.BankAccountResponse, error) {
+	arg := repository.CreateBankAccountParams{
+		SellerID:          sellerID,
+		BankAccountName:   payload.BankAccountName,
+		BankAccountHolder: payload.BankAccountHolder,
+		BankAccountNumber: payload.BankAccountNumber,
+		IsDefault:         payload.IsDefault,
+	}
+
+	account, err := u.repo.CreateBankAccount(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	response := converter.ToBankAccountResponse(account)
+	return &response, nil
+}
+
+// UpdateBankAccount only honors isDefault=true. The default account is
+// changed by promoting another account, never by unsetting the current one.
+func (u *BankAccountUsecase) UpdateBankAccount(ctx context.Context, ID, sellerID int, payload *dto.BankAccountUpdatePayload) (*dto.BankAccountResponse, error) {
+	arg := repository.UpdateBankAccountParams{
+		ID:                ID,
+		SellerID:          sellerID,
+		BankAccountName:   payload.BankAccountName,
+		BankAccountHolder: payload.BankAccountHolder,
+		BankAccountNumber: payload.BankAccountNumber,
+		MakeDefault:       payload.IsDefault != nil && *payload.IsDefault,
+	}
+
+	account, err := u.repo.UpdateBankAccount(ctx, arg)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "bank account not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToBankAccountResponse(account)
+	return &response, nil
+}
+
+func (u *BankAccountUsecase) DeleteBankAccount(ctx context.Context, ID, sellerID int) error {
+	err := u.repo.DeleteBankAccount(ctx, ID, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return errors.Wrap(customErrors.ErrNotFound, "bank account not found")
+		}
+		return err
+	}
+	return nil
+}
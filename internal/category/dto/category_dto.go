@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CategoryPayload struct {
+	ParentID *string `json:"parentId" validate:"omitnil,number"`
+	Name     string  `json:"name" validate:"required,min=1,max=64"`
+	Slug     string  `json:"slug" validate:"required,max=64,slug"`
+	IsActive *bool   `json:"isActive"`
+}
+
+// CategoryUpdatePayload is decoded from a JSON Merge Patch, a null parentId
+// moves the category to the top level.
+type CategoryUpdatePayload struct {
+	ParentID     *string `json:"parentId" validate:"omitnil,number"`
+	RemoveParent bool    `json:"-"`
+	Name         *string `json:"name" validate:"omitnil,min=1,max=64"`
+	Slug         *string `json:"slug" validate:"omitnil,max=64,slug"`
+	IsActive     *bool   `json:"isActive"`
+}
+
+type CategoryResponse struct {
+	CategoryID string    `json:"categoryId"`
+	ParentID   *string   `json:"parentId"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	IsActive   bool      `json:"isActive"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
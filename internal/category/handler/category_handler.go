@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"tutup-lapak/internal/category/dto"
+	"tutup-lapak/internal/category/usecase"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type CategoryHandler struct {
+	UseCase  *usecase.CategoryUsecase
+	Validate *validator.Validate
+}
+
+func NewCategoryHandler(useCase *usecase.CategoryUsecase, validate *validator.Validate) *CategoryHandler {
+	return &CategoryHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+// ListCategories lists the active categories to everyone.
+func (h *CategoryHandler) ListCategories(ctx echo.Context) error {
+	categories, err := h.UseCase.ListCategories(ctx.Request().Context(), false)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, categories)
+}
+
+// ListAllCategories lists inactive categories as well for administrators.
+func (h *CategoryHandler) ListAllCategories(ctx echo.Context) error {
+	categories, err := h.UseCase.ListCategories(ctx.Request().Context(), true)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, categories)
+}
+
+func (h *CategoryHandler) CreateCategory(ctx echo.Context) error {
+	var payload = new(dto.CategoryPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	category, err := h.UseCase.CreateCategory(ctx.Request().Context(), payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) UpdateCategory(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var payload = new(dto.CategoryUpdatePayload)
+
+	if err := bindMergePatch(ctx.Request().Body, payload); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	category, err := h.UseCase.UpdateCategory(ctx.Request().Context(), id, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, category)
+}
+
+// bindMergePatch decodes a JSON Merge Patch (RFC 7396) document. Only the
+// parent can be removed, a null parentId sets RemoveParent and other null
+// members are rejected along with members that are not part of the payload.
+func bindMergePatch(body io.Reader, payload *dto.CategoryUpdatePayload) error {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, err.Error())
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, "patch must be a JSON object")
+	}
+	if len(members) == 0 {
+		return errors.Wrap(customErrors.ErrBadRequest, "patch has no fields to update")
+	}
+	for field, value := range members {
+		if string(value) != "null" {
+			continue
+		}
+		if field != "parentId" {
+			return errors.Wrapf(customErrors.ErrBadRequest, "field %s cannot be removed", field)
+		}
+		payload.RemoveParent = true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, err.Error())
+	}
+
+	return nil
+}
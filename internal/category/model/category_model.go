@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type Category struct {
+	ID        int
+	ParentID  *int
+	Name      string
+	Slug      string
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/category/dto"
+	"tutup-lapak/internal/category/model"
+)
+
+func ToCategoryResponse(category model.Category) dto.CategoryResponse {
+	var parentID *string
+	if category.ParentID != nil {
+		id := strconv.Itoa(*category.ParentID)
+		parentID = &id
+	}
+
+	return dto.CategoryResponse{
+		CategoryID: strconv.Itoa(category.ID),
+		ParentID:   parentID,
+		Name:       category.Name,
+		Slug:       category.Slug,
+		IsActive:   category.IsActive,
+		CreatedAt:  category.CreatedAt,
+		UpdatedAt:  category.UpdatedAt,
+	}
+}
+
+func ToCategoryResponses(categories []model.Category) []dto.CategoryResponse {
+	responses := make([]dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		responses = append(responses, ToCategoryResponse(category))
+	}
+	return responses
+}
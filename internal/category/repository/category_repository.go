@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+
+	"tutup-lapak/internal/category/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCategoryRepository(pool *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{pool: pool}
+}
+
+var categoryConstraints = map[string]string{
+	"categories_name_key": "category name already exists",
+	"categories_slug_key": "category slug already exists",
+}
+
+const listCategoriesQuery = `-- name: ListCategories :many
+SELECT id, parent_id, name, slug, is_active, created_at, updated_at FROM categories
+WHERE $1::BOOLEAN OR is_active
+ORDER BY name ASC
+`
+
+func (r *CategoryRepository) ListCategories(ctx context.Context, includeInactive bool) ([]model.Category, error) {
+	rows, err := r.pool.Query(ctx, listCategoriesQuery, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	return collectCategories(rows)
+}
+
+const getCategoryQuery = `-- name: GetCategory :one
+SELECT id, parent_id, name, slug, is_active, created_at, updated_at FROM categories
+WHERE id = $1
+`
+
+func (r *CategoryRepository) GetCategory(ctx context.Context, id int) (model.Category, error) {
+	row := r.pool.QueryRow(ctx, getCategoryQuery, id)
+	return scanCategory(row)
+}
+
+const getCategoriesByNamesQuery = `-- name: GetCategoriesByNames :many
+SELECT id, parent_id, name, slug, is_active, created_at, updated_at FROM categories
+WHERE name = ANY($1::TEXT[])
+`
+
+// GetCategoriesByNames looks categories up by name, slugs are not matched so
+// a value can't name one category and be the slug of another.
+func (r *CategoryRepository) GetCategoriesByNames(ctx context.Context, names []string) ([]model.Category, error) {
+	rows, err := r.pool.Query(ctx, getCategoriesByNamesQuery, names)
+	if err != nil {
+		return nil, err
+	}
+	return collectCategories(rows)
+}
+
+const getCategoryAncestorIDsQuery = `-- name: GetCategoryAncestorIDs :many
+WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM categories WHERE id = $1
+	UNION
+	SELECT c.id, c.parent_id FROM categories c
+	JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT id FROM ancestors
+`
+
+// GetCategoryAncestorIDs returns the category itself and every category
+// above it.
+func (r *CategoryRepository) GetCategoryAncestorIDs(ctx context.Context, id int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, getCategoryAncestorIDsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+const createCategoryQuery = `-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug, is_active) VALUES ($1, $2, $3, $4)
+RETURNING id, parent_id, name, slug, is_active, created_at, updated_at
+`
+
+type CreateCategoryParams struct {
+	ParentID *int
+	Name     string
+	Slug     string
+	IsActive bool
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, arg CreateCategoryParams) (model.Category, error) {
+	row := r.pool.QueryRow(ctx, createCategoryQuery,
+		arg.ParentID,
+		arg.Name,
+		arg.Slug,
+		arg.IsActive,
+	)
+	category, err := scanCategory(row)
+	if err != nil {
+		return model.Category{}, customErrors.HandlePgConstraintError(err, "failed to create category", categoryConstraints)
+	}
+	return category, nil
+}
+
+const updateCategoryQuery = `-- name: UpdateCategory :one
+UPDATE categories
+SET
+	parent_id = CASE WHEN $2::BOOLEAN THEN $3 ELSE parent_id END,
+	name = COALESCE($4, name),
+	slug = COALESCE($5, slug),
+	is_active = COALESCE($6, is_active)
+WHERE id = $1
+RETURNING id, parent_id, name, slug, is_active, created_at, updated_at
+`
+
+type UpdateCategoryParams struct {
+	ID        int
+	SetParent bool
+	ParentID  *int
+	Name      *string
+	Slug      *string
+	IsActive  *bool
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (model.Category, error) {
+	row := r.pool.QueryRow(ctx, updateCategoryQuery,
+		arg.ID,
+		arg.SetParent,
+		arg.ParentID,
+		arg.Name,
+		arg.Slug,
+		arg.IsActive,
+	)
+	category, err := scanCategory(row)
+	if err != nil {
+		return model.Category{}, customErrors.HandlePgConstraintError(err, "failed to update category", categoryConstraints)
+	}
+	return category, nil
+}
+
+func collectCategories(rows pgx.Rows) ([]model.Category, error) {
+	defer rows.Close()
+
+	var items []model.Category
+	for rows.Next() {
+		i, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanCategory(row pgx.Row) (model.Category, error) {
+	var i model.Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
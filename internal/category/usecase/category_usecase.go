@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"tutup-lapak/internal/category/dto"
+	"tutup-lapak/internal/category/model/converter"
+	"tutup-lapak/internal/category/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+type CategoryUsecase struct {
+	repo *repository.CategoryRepository
+}
+
+func NewCategoryUsecase(repo *repository.CategoryRepository) *CategoryUsecase {
+	return &CategoryUsecase{
+		repo: repo,
+	}
+}
+
+func (u *CategoryUsecase) ListCategories(ctx context.Context, includeInactive bool) ([]dto.CategoryResponse, error) {
+	categories, err := u.repo.ListCategories(ctx, includeInactive)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get categories")
+	}
+
+	return converter.ToCategoryResponses(categories), nil
+}
+
+func (u *CategoryUsecase) CreateCategory(ctx context.Context, payload *dto.CategoryPayload) (*dto.CategoryResponse, error) {
+	arg := repository.CreateCategoryParams{
+		Name:     payload.Name,
+		Slug:     payload.Slug,
+		IsActive: payload.IsActive == nil || *payload.IsActive,
+	}
+
+	if payload.ParentID != nil {
+		parentID, err := u.findParent(ctx, *payload.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		arg.ParentID = &parentID
+	}
+
+	category, err := u.repo.CreateCategory(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	response := converter.ToCategoryResponse(category)
+	return &response, nil
+}
+
+// UpdateCategory refuses to move a category below itself or one of its
+// descendants, which would detach the subtree from the hierarchy.
+func (u *CategoryUsecase) UpdateCategory(ctx context.Context, ID int, payload *dto.CategoryUpdatePayload) (*dto.CategoryResponse, error) {
+	arg := repository.UpdateCategoryParams{
+		ID:       ID,
+		Name:     payload.Name,
+		Slug:     payload.Slug,
+		IsActive: payload.IsActive,
+	}
+
+	if payload.RemoveParent {
+		arg.SetParent = true
+	} else if payload.ParentID != nil {
+		parentID, err := u.findParent(ctx, *payload.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		ancestorIDs, err := u.repo.GetCategoryAncestorIDs(ctx, parentID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get category ancestors")
+		}
+		if slices.Contains(ancestorIDs, ID) {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "category cannot be moved below itself")
+		}
+		arg.SetParent = true
+		arg.ParentID = &parentID
+	}
+
+	category, err := u.repo.UpdateCategory(ctx, arg)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "category not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToCategoryResponse(category)
+	return &response, nil
+}
+
+func (u *CategoryUsecase) findParent(ctx context.Context, parentID string) (int, error) {
+	id, err := strconv.Atoi(parentID)
+	if err != nil {
+		return 0, errors.Wrap(customErrors.ErrBadRequest, "parent category not found")
+	}
+
+	parent, err := u.repo.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return 0, errors.Wrap(customErrors.ErrBadRequest, "parent category not found")
+		}
+		return 0, errors.Wrap(err, "failed to get parent category")
+	}
+	return parent.ID, nil
+}
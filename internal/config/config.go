@@ -1,8 +1,24 @@
 package config
 
 import (
+	"context"
 	"time"
 	"tutup-lapak/db"
+	alert_handler "tutup-lapak/internal/alert/handler"
+	alert_repository "tutup-lapak/internal/alert/repository"
+	alert_usecase "tutup-lapak/internal/alert/usecase"
+	api_key_handler "tutup-lapak/internal/apikey/handler"
+	api_key_repository "tutup-lapak/internal/apikey/repository"
+	api_key_usecase "tutup-lapak/internal/apikey/usecase"
+	auth_handler "tutup-lapak/internal/auth/handler"
+	auth_repository "tutup-lapak/internal/auth/repository"
+	auth_usecase "tutup-lapak/internal/auth/usecase"
+	bank_account_handler "tutup-lapak/internal/bankaccount/handler"
+	bank_account_repository "tutup-lapak/internal/bankaccount/repository"
+	bank_account_usecase "tutup-lapak/internal/bankaccount/usecase"
+	category_handler "tutup-lapak/internal/category/handler"
+	category_repository "tutup-lapak/internal/category/repository"
+	category_usecase "tutup-lapak/internal/category/usecase"
 	file_handler "tutup-lapak/internal/file/handler"
 	file_repository "tutup-lapak/internal/file/repository"
 	file_usecase "tutup-lapak/internal/file/usecase"
@@ -10,11 +26,20 @@ import (
 	product_handler "tutup-lapak/internal/product/handler"
 	product_repository "tutup-lapak/internal/product/repository"
 	product_usecase "tutup-lapak/internal/product/usecase"
+	promotion_handler "tutup-lapak/internal/promotion/handler"
+	promotion_repository "tutup-lapak/internal/promotion/repository"
+	promotion_usecase "tutup-lapak/internal/promotion/usecase"
 	purchase_handler "tutup-lapak/internal/purchase/handler"
 	purchase_repository "tutup-lapak/internal/purchase/repository"
 	purchase_usecase "tutup-lapak/internal/purchase/usecase"
 	"tutup-lapak/internal/routes"
+	security_repository "tutup-lapak/internal/security/repository"
+	security_usecase "tutup-lapak/internal/security/usecase"
+	user_handler "tutup-lapak/internal/user/handler"
+	user_repository "tutup-lapak/internal/user/repository"
+	user_usecase "tutup-lapak/internal/user/usecase"
 	"tutup-lapak/pkg/dotenv"
+	"tutup-lapak/pkg/jwt"
 
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/go-playground/validator/v10"
@@ -24,43 +49,101 @@ import (
 )
 
 type BootstrapConfig struct {
+	Ctx        context.Context
 	Env        *dotenv.Env
 	App        *echo.Echo
 	DB         *db.Postgres
 	Log        *logrus.Logger
 	Validator  *validator.Validate
 	S3Uploader *manager.Uploader
+	JWTKeys    *jwt.KeySet
 }
 
 func Bootstrap(config *BootstrapConfig) {
+	// RealIP keys the login and payment rate limits, it must not come from
+	// headers the client controls
+	config.App.IPExtractor = NewIPExtractor(config.Env)
+
 	// * Middleware
 	config.App.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Skipper:      middleware.DefaultSkipper,
+		// the timeout handler buffers responses, which would hold a streamed
+		// export in memory. An import outlasting it would keep writing after
+		// the client got a timeout, it runs under its own longer deadline.
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/v1/product/export" || c.Path() == "/v1/product/import"
+		},
 		ErrorMessage: "Timeout",
 		Timeout:      30 * time.Second,
 	}))
 
-	authMiddleware := custom_middleware.NewAuthMiddleware(config.Env)
+	notifier := NewNotifier(config.Env, config.Log)
+
+	securityRepo := security_repository.NewSecurityRepository(config.DB.Pool)
+	securityUsecase := security_usecase.NewSecurityUsecase(securityRepo)
+	rateLimitMiddleware := custom_middleware.NewRateLimitMiddleware(securityUsecase)
+	StartRateLimitPurger(config.Ctx, securityUsecase, config.Log)
+
+	authRepo := auth_repository.NewAuthRepository(config.DB.Pool)
+	authUsecase := auth_usecase.NewAuthUsecase(authRepo, config.JWTKeys, notifier, securityUsecase)
+	authHandler := auth_handler.NewAuthHandler(authUsecase, config.Validator)
+
+	apiKeyRepo := api_key_repository.NewAPIKeyRepository(config.DB.Pool)
+	apiKeyUsecase := api_key_usecase.NewAPIKeyUsecase(apiKeyRepo)
+	apiKeyHandler := api_key_handler.NewAPIKeyHandler(apiKeyUsecase, config.Validator)
+
+	authMiddleware := custom_middleware.NewAuthMiddleware(config.JWTKeys, authUsecase, apiKeyUsecase)
+
+	fileRepo := file_repository.NewFileRepository(config.DB.Pool)
+
+	categoryRepo := category_repository.NewCategoryRepository(config.DB.Pool)
+	categoryUsecase := category_usecase.NewCategoryUsecase(categoryRepo)
+	categoryHandler := category_handler.NewCategoryHandler(categoryUsecase, config.Validator)
 
 	productRepo := product_repository.NewProductRepo(config.DB.Pool)
-	productUsecase := product_usecase.NewProductUsecase(productRepo)
+	productUsecase := product_usecase.NewProductUsecase(productRepo, fileRepo, categoryRepo)
 	productHandler := product_handler.NewProductHandler(productUsecase, config.Validator)
+	StartPriceScheduler(config.Ctx, productUsecase, config.Log)
+
+	promotionRepo := promotion_repository.NewPromotionRepository(config.DB.Pool)
+	promotionUsecase := promotion_usecase.NewPromotionUsecase(promotionRepo, categoryRepo)
+	promotionHandler := promotion_handler.NewPromotionHandler(promotionUsecase, config.Validator)
+
+	alertChannel, alertNotifier := NewAlertNotifier(config.Env, config.Log, notifier)
+	alertRepo := alert_repository.NewAlertRepository(config.DB.Pool)
+	alertUsecase := alert_usecase.NewAlertUsecase(alertRepo, alertChannel, alertNotifier, config.Log)
+	alertHandler := alert_handler.NewAlertHandler(alertUsecase, config.Validator)
+	StartAlertDispatcher(config.Ctx, alertUsecase, config.Log)
 
 	purchaseRepo := purchase_repository.NewPurchaseRepository(config.DB.Pool)
-	purchaseUsecase := purchase_usecase.NewPurchaseUseCase(purchaseRepo, productRepo)
+	purchaseUsecase := purchase_usecase.NewPurchaseUseCase(purchaseRepo, productRepo, promotionRepo)
 	purchaseHandler := purchase_handler.NewPurchaseHandler(purchaseUsecase, config.Validator)
 
-	fileRepo := file_repository.NewFileRepository(config.DB.Pool)
 	fileUsecase := file_usecase.NewFileUseCase(config.S3Uploader, config.Env, fileRepo)
 	fileHandler := file_handler.NewFileHandler(fileUsecase, config.Log)
 
+	userRepo := user_repository.NewUserRepository(config.DB.Pool)
+	userUsecase := user_usecase.NewUserUsecase(userRepo, fileRepo)
+	userHandler := user_handler.NewUserHandler(userUsecase, config.Validator)
+
+	bankAccountRepo := bank_account_repository.NewBankAccountRepository(config.DB.Pool)
+	bankAccountUsecase := bank_account_usecase.NewBankAccountUsecase(bankAccountRepo)
+	bankAccountHandler := bank_account_handler.NewBankAccountHandler(bankAccountUsecase, config.Validator)
+
 	routes := routes.RouteConfig{
-		App:             config.App,
-		S3Uploader:      config.S3Uploader,
-		Middleware:      authMiddleware,
-		ProductHandler:  productHandler,
-		PurchaseHandler: purchaseHandler,
-		FileHandler:     fileHandler,
+		App:                config.App,
+		S3Uploader:         config.S3Uploader,
+		Middleware:         authMiddleware,
+		RateLimit:          rateLimitMiddleware,
+		AuthHandler:        authHandler,
+		ProductHandler:     productHandler,
+		PurchaseHandler:    purchaseHandler,
+		FileHandler:        fileHandler,
+		UserHandler:        userHandler,
+		BankAccountHandler: bankAccountHandler,
+		APIKeyHandler:      apiKeyHandler,
+		CategoryHandler:    categoryHandler,
+		PromotionHandler:   promotionHandler,
+		AlertHandler:       alertHandler,
 	}
 
 	routes.SetupRoutes()
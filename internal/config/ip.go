@@ -0,0 +1,39 @@
+package config
+
+import (
+	"log"
+	"net"
+	"strings"
+	"tutup-lapak/pkg/dotenv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor reads the client IP from the connection itself unless
+// TRUSTED_PROXIES lists the comma separated CIDRs of the proxies in front of
+// the app. Only then is X-Forwarded-For honored, and only the hops added by
+// those proxies, so clients can't pick the IP they are rate limited under.
+func NewIPExtractor(env *dotenv.Env) echo.IPExtractor {
+	var options []echo.TrustOption
+	for _, entry := range strings.Split(env.TRUSTED_PROXIES, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		_, ipRange, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Fatal("invalid TRUSTED_PROXIES entry ", entry)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	if len(options) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// the defaults trust every loopback and private address, only the listed
+	// proxies are trusted here
+	options = append(options, echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false))
+	return echo.ExtractIPFromXFFHeader(options...)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"log"
+	"strings"
+	"tutup-lapak/pkg/dotenv"
+	"tutup-lapak/pkg/jwt"
+)
+
+// NewJWTKeySet loads the signing keys listed in JWT_KEYS as comma separated
+// kid=path pairs, e.g. "2025-01=/keys/2025-01.pem,2025-06=/keys/2025-06.pem".
+func NewJWTKeySet(env *dotenv.Env) *jwt.KeySet {
+	keyFiles := make(map[string]string)
+	for _, entry := range strings.Split(env.JWT_KEYS, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(entry, "=")
+		if !found {
+			log.Fatal("invalid JWT_KEYS entry ", entry)
+		}
+		keyFiles[strings.TrimSpace(kid)] = strings.TrimSpace(path)
+	}
+
+	keySet, err := jwt.NewKeySet(env.JWT_SECRET, keyFiles, env.JWT_ACTIVE_KID)
+	if err != nil {
+		log.Fatal("unable to load JWT keys ", err.Error())
+	}
+
+	return keySet
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"log"
+	"tutup-lapak/pkg/dotenv"
+	"tutup-lapak/pkg/notifier"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewNotifier picks the delivery backend from NOTIFIER: "log" (default),
+// "file" or "smtp". Phone messages always go to the log since there is no
+// SMS gateway yet.
+func NewNotifier(env *dotenv.Env, logger *logrus.Logger) notifier.Notifier {
+	logNotifier := notifier.NewLogNotifier(logger)
+
+	switch env.NOTIFIER {
+	case "", "log":
+		return logNotifier
+	case "file":
+		path := env.NOTIFIER_FILE
+		if path == "" {
+			path = "notifications.jsonl"
+		}
+		return notifier.NewFileNotifier(path)
+	case "smtp":
+		return notifier.ChannelNotifier{
+			notifier.ChannelEmail: notifier.NewSMTPNotifier(
+				env.SMTP_HOST,
+				env.SMTP_PORT,
+				env.SMTP_USERNAME,
+				env.SMTP_PASSWORD,
+				env.SMTP_FROM,
+			),
+			notifier.ChannelPhone: logNotifier,
+		}
+	default:
+		log.Fatal("unknown NOTIFIER ", env.NOTIFIER)
+		return nil
+	}
+}
+
+// NewAlertNotifier picks where stock alerts go from ALERT_NOTIFIER: "log"
+// (default), "webhook" posting to ALERT_WEBHOOK_URL signed with
+// ALERT_WEBHOOK_KEY, or "email" to the seller through sellerNotifier. It
+// returns the channel alerts are sent on.
+func NewAlertNotifier(env *dotenv.Env, logger *logrus.Logger, sellerNotifier notifier.Notifier) (string, notifier.Notifier) {
+	switch env.ALERT_NOTIFIER {
+	case "", "log":
+		return notifier.ChannelLog, notifier.NewLogNotifier(logger)
+	case "webhook":
+		if env.ALERT_WEBHOOK_URL == "" {
+			log.Fatal("ALERT_WEBHOOK_URL is required by the webhook alert notifier")
+		}
+		return notifier.ChannelWebhook, notifier.NewWebhookNotifier(env.ALERT_WEBHOOK_URL, env.ALERT_WEBHOOK_KEY)
+	case "email":
+		return notifier.ChannelEmail, sellerNotifier
+	default:
+		log.Fatal("unknown ALERT_NOTIFIER ", env.ALERT_NOTIFIER)
+		return "", nil
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"time"
+	alert_usecase "tutup-lapak/internal/alert/usecase"
+	product_usecase "tutup-lapak/internal/product/usecase"
+	security_usecase "tutup-lapak/internal/security/usecase"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	priceScheduleInterval  = time.Minute
+	rateLimitPurgeInterval = time.Hour
+	alertDeliveryInterval  = time.Minute
+)
+
+// StartPriceScheduler applies and reverts scheduled product prices in the
+// background until ctx is canceled.
+func StartPriceScheduler(ctx context.Context, productUsecase *product_usecase.ProductUsecase, logger *logrus.Logger) {
+	runEvery(ctx, priceScheduleInterval, func(ctx context.Context) {
+		applied, err := productUsecase.ApplyPriceSchedules(ctx)
+		if err != nil {
+			logger.WithError(err).Error("failed to apply price schedules")
+		}
+		if applied > 0 {
+			logger.WithField("applied", applied).Info("applied price schedules")
+		}
+	})
+}
+
+// StartRateLimitPurger deletes expired rate limit counters in the background
+// until ctx is canceled.
+func StartRateLimitPurger(ctx context.Context, securityUsecase *security_usecase.SecurityUsecase, logger *logrus.Logger) {
+	runEvery(ctx, rateLimitPurgeInterval, func(ctx context.Context) {
+		deleted, err := securityUsecase.PurgeRateLimits(ctx)
+		if err != nil {
+			logger.WithError(err).Error("failed to purge rate limits")
+		}
+		if deleted > 0 {
+			logger.WithField("deleted", deleted).Info("purged rate limits")
+		}
+	})
+}
+
+// StartAlertDispatcher delivers pending stock alerts in the background until
+// ctx is canceled, so a slow channel never holds up a payment.
+func StartAlertDispatcher(ctx context.Context, alertUsecase *alert_usecase.AlertUsecase, logger *logrus.Logger) {
+	runEvery(ctx, alertDeliveryInterval, func(ctx context.Context) {
+		delivered, err := alertUsecase.DeliverPendingAlerts(ctx)
+		if err != nil {
+			logger.WithError(err).Error("failed to deliver stock alerts")
+		}
+		if delivered > 0 {
+			logger.WithField("delivered", delivered).Info("delivered stock alerts")
+		}
+	})
+}
+
+// runEvery calls job once per interval until ctx is canceled. Each run may
+// take at most one interval.
+func runEvery(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			runCtx, cancel := context.WithTimeout(ctx, interval)
+			job(runCtx)
+			cancel()
+		}
+	}()
+}
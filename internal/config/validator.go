@@ -12,6 +12,8 @@ import (
 
 var (
 	sortByCache = make(map[string]bool)
+	phoneRegex  = regexp.MustCompile(`\+\d{1,15}$`)
+	slugRegex   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 )
 
 func NewValidator() *validator.Validate {
@@ -20,6 +22,8 @@ func NewValidator() *validator.Validate {
 	validate.RegisterValidation("is_uri", uriValidator)
 	validate.RegisterValidation("sort_by", productSortByValidator)
 	validate.RegisterValidation("contact_detail_validator", contactDetailValidation)
+	validate.RegisterValidation("phone_number", phoneNumberValidator)
+	validate.RegisterValidation("slug", slugValidator)
 	return validate
 }
 
@@ -106,10 +110,17 @@ func contactDetailValidation(fl validator.FieldLevel) bool {
 	contactDetail := fl.Field().String()
 
 	if contactType == "phone" {
-		phoneRegex := regexp.MustCompile(`\+\d{1,15}$`)
 		return phoneRegex.MatchString(contactDetail)
 	} else if contactType == "email" {
 		return validator.New().Var(contactDetail, "email") == nil
 	}
 	return false
 }
+
+func phoneNumberValidator(fl validator.FieldLevel) bool {
+	return phoneRegex.MatchString(fl.Field().String())
+}
+
+func slugValidator(fl validator.FieldLevel) bool {
+	return slugRegex.MatchString(fl.Field().String())
+}
@@ -4,6 +4,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	file_usecase "tutup-lapak/internal/file/usecase"
+	custom_middleware "tutup-lapak/internal/middleware"
 	customErrors "tutup-lapak/pkg/custom-errors"
 	"tutup-lapak/pkg/response"
 
@@ -25,6 +26,11 @@ func NewFileHandler(Usecase *file_usecase.FileUsecase, logger *logrus.Logger) *F
 }
 
 func (h *FileHandler) UploadFile(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
 	fileHeader, err := ctx.FormFile("file")
 	if err != nil {
 		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
@@ -43,7 +49,7 @@ func (h *FileHandler) UploadFile(ctx echo.Context) error {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
-	fileResponse, err := h.FileUsecase.UploadFile(ctx.Request().Context(), file, *fileType)
+	fileResponse, err := h.FileUsecase.UploadFile(ctx.Request().Context(), sellerID, file, *fileType)
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
@@ -2,6 +2,7 @@ package model
 
 type File struct {
 	ID           int
+	SellerID     *int
 	URI          string
 	ThumbnailURI string
 }
@@ -17,22 +17,74 @@ func NewFileRepository(pool *pgxpool.Pool) *FileRepository {
 }
 
 const insertFileQuery = `-- name: InsertFile :one
-INSERT INTO files (uri, thumbnail_uri) VALUES ($1, $2) RETURNING id, uri, thumbnail_uri
+INSERT INTO files (seller_id, uri, thumbnail_uri) VALUES ($1, $2, $3) RETURNING id, seller_id, uri, thumbnail_uri
 `
 
 type InsertFileParams struct {
+	SellerID     int
 	URI          string
 	ThumbnailURI string
 }
 
 func (r *FileRepository) InsertFile(ctx context.Context, arg InsertFileParams) (model.File, error) {
-	row := r.pool.QueryRow(ctx, insertFileQuery, arg.URI, arg.ThumbnailURI)
+	row := r.pool.QueryRow(ctx, insertFileQuery, arg.SellerID, arg.URI, arg.ThumbnailURI)
 
 	var file model.File
 	err := row.Scan(
 		&file.ID,
+		&file.SellerID,
 		&file.URI,
 		&file.ThumbnailURI,
 	)
 	return file, err
 }
+
+const getFileQuery = `-- name: GetFile :one
+SELECT id, seller_id, uri, thumbnail_uri FROM files
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *FileRepository) GetFile(ctx context.Context, fileID int) (model.File, error) {
+	row := r.pool.QueryRow(ctx, getFileQuery, fileID)
+
+	var file model.File
+	err := row.Scan(
+		&file.ID,
+		&file.SellerID,
+		&file.URI,
+		&file.ThumbnailURI,
+	)
+	return file, err
+}
+
+const listFilesQuery = `-- name: ListFiles :many
+SELECT id, seller_id, uri, thumbnail_uri FROM files
+WHERE id = ANY($1::BIGINT[])
+`
+
+func (r *FileRepository) ListFiles(ctx context.Context, fileIDs []int) ([]model.File, error) {
+	rows, err := r.pool.Query(ctx, listFilesQuery, fileIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.File
+	for rows.Next() {
+		var file model.File
+		if err := rows.Scan(
+			&file.ID,
+			&file.SellerID,
+			&file.URI,
+			&file.ThumbnailURI,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
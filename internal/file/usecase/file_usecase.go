@@ -45,7 +45,7 @@ func NewFileUseCase(uploader *manager.Uploader, env *dotenv.Env, fileRepo *repos
 	}
 }
 
-func (u *FileUsecase) UploadFile(ctx context.Context, file multipart.File, fileType string) (*dto.FileUploadResponse, error) {
+func (u *FileUsecase) UploadFile(ctx context.Context, sellerID int, file multipart.File, fileType string) (*dto.FileUploadResponse, error) {
 	defer file.Close()
 
 	filename := u.generateFilename(fileType)
@@ -65,6 +65,7 @@ func (u *FileUsecase) UploadFile(ctx context.Context, file multipart.File, fileT
 	}(u.S3Uploader, file, u.Env.AWS_S3_BUCKET_NAME, filename)
 
 	arg := repository.InsertFileParams{
+		SellerID:     sellerID,
 		URI:          fileUri,
 		ThumbnailURI: fileUri,
 	}
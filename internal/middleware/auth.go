@@ -2,9 +2,14 @@ package custom_middleware
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
+	api_key_model "tutup-lapak/internal/apikey/model"
+	api_key_usecase "tutup-lapak/internal/apikey/usecase"
+	auth_model "tutup-lapak/internal/auth/model"
+	auth_usecase "tutup-lapak/internal/auth/usecase"
 	customErrors "tutup-lapak/pkg/custom-errors"
-	"tutup-lapak/pkg/dotenv"
 	jwt "tutup-lapak/pkg/jwt"
 	"tutup-lapak/pkg/response"
 
@@ -12,31 +17,80 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	userContextKey   = "user"
+	scopesContextKey = "apiKeyScopes"
+
+	bearerScheme = "Bearer"
+	apiKeyScheme = "ApiKey"
+)
+
 type AuthConfig struct {
-	Env *dotenv.Env
+	JWTKeys       *jwt.KeySet
+	AuthUsecase   *auth_usecase.AuthUsecase
+	APIKeyUsecase *api_key_usecase.APIKeyUsecase
 }
 
-func NewAuthMiddleware(env *dotenv.Env) *AuthConfig {
+func NewAuthMiddleware(jwtKeys *jwt.KeySet, authUsecase *auth_usecase.AuthUsecase, apiKeyUsecase *api_key_usecase.APIKeyUsecase) *AuthConfig {
 	return &AuthConfig{
-		Env: env,
+		JWTKeys:       jwtKeys,
+		AuthUsecase:   authUsecase,
+		APIKeyUsecase: apiKeyUsecase,
 	}
 }
 
+// Authenticate only accepts session JWTs.
 func (a *AuthConfig) Authenticate() echo.MiddlewareFunc {
+	return a.authenticate(false)
+}
+
+// AuthenticateWithAPIKey also accepts seller API keys. Routes using it should
+// state the scope they need with RequireScope.
+func (a *AuthConfig) AuthenticateWithAPIKey() echo.MiddlewareFunc {
+	return a.authenticate(true)
+}
+
+func (a *AuthConfig) authenticate(allowAPIKey bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			jwtToken, err := extractJWTTokenFromHeader(ctx.Request())
+			scheme, credential, err := extractJWTTokenFromHeader(ctx.Request())
 			if err != nil {
 				return ctx.JSON(response.WriteErrorResponse(err))
 			}
 
-			claim, err := jwt.ClaimToken(jwtToken, a.Env.JWT_SECRET)
+			if scheme == apiKeyScheme {
+				if !allowAPIKey {
+					err = errors.Wrap(customErrors.ErrUnauthorized, "api keys are not accepted on this route")
+					return ctx.JSON(response.WriteErrorResponse(err))
+				}
+
+				identity, err := a.APIKeyUsecase.Authenticate(ctx.Request().Context(), credential)
+				if err != nil {
+					return ctx.JSON(response.WriteErrorResponse(err))
+				}
+
+				// API keys never carry elevated roles
+				ctx.Set(userContextKey, &jwt.JWTClaim{ID: identity.SellerID, Role: auth_model.RoleSeller})
+				ctx.Set(scopesContextKey, identity.Scopes)
+				return next(ctx)
+			}
+
+			claim, err := a.JWTKeys.ClaimToken(credential)
 			if err != nil {
 				err = errors.Wrap(customErrors.ErrUnauthorized, err.Error())
 				return ctx.JSON(response.WriteErrorResponse(err))
 			}
 
-			ctx.Set("user", claim)
+			active, err := a.AuthUsecase.IsSessionActive(ctx.Request().Context(), claim.SessionID)
+			if err != nil {
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+			if !active {
+				err = errors.Wrap(customErrors.ErrUnauthorized, "session revoked")
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			ctx.Set(userContextKey, claim)
 
 			// default user passing middleware if token is valid
 			return next(ctx)
@@ -44,11 +98,76 @@ func (a *AuthConfig) Authenticate() echo.MiddlewareFunc {
 	}
 }
 
-func extractJWTTokenFromHeader(r *http.Request) (string, error) {
+// RequireScope checks the scopes of API key requests. Session JWTs act on
+// behalf of the seller and are not scoped. It must run after
+// AuthenticateWithAPIKey.
+func (a *AuthConfig) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			scopes, isAPIKey := ctx.Get(scopesContextKey).([]string)
+			if isAPIKey && !api_key_model.HasScope(scopes, scope) {
+				err := errors.Wrap(customErrors.ErrForbidden, "api key is missing scope "+scope)
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// RequireRole only lets through claims carrying one of the given roles. It
+// must run after Authenticate.
+func (a *AuthConfig) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			claim, err := GetClaim(ctx)
+			if err != nil {
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			if !slices.Contains(roles, claim.Role) {
+				err = errors.Wrap(customErrors.ErrForbidden, "insufficient role")
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// extractJWTTokenFromHeader returns the scheme and credential of the
+// Authorization header, either "Bearer <jwt>" or "ApiKey <key>".
+func extractJWTTokenFromHeader(r *http.Request) (string, string, error) {
 	authToken := r.Header.Get("Authorization")
 	if authToken == "" {
-		return "", errors.Wrap(customErrors.ErrUnauthorized, "missing auth token")
+		return "", "", errors.Wrap(customErrors.ErrUnauthorized, "missing auth token")
+	}
+
+	scheme, token, found := strings.Cut(authToken, " ")
+	if !found || token == "" || (scheme != bearerScheme && scheme != apiKeyScheme) {
+		return "", "", errors.Wrap(customErrors.ErrUnauthorized, "invalid auth token")
+	}
+
+	return scheme, token, nil
+}
+
+// GetClaim returns the claim stored by Authenticate.
+func GetClaim(ctx echo.Context) (*jwt.JWTClaim, error) {
+	claim, ok := ctx.Get(userContextKey).(*jwt.JWTClaim)
+	if !ok || claim == nil || claim.ID == 0 {
+		return nil, errors.Wrap(customErrors.ErrUnauthorized, "missing seller identity")
+	}
+
+	return claim, nil
+}
+
+// GetSellerID returns the seller ID of the claim stored by Authenticate. It
+// fails instead of falling back to a default seller when no claim is present.
+func GetSellerID(ctx echo.Context) (int, error) {
+	claim, err := GetClaim(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	return authToken[len("Bearer "):], nil
+	return claim.ID, nil
 }
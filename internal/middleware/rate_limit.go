@@ -0,0 +1,68 @@
+package custom_middleware
+
+import (
+	"net/http"
+
+	security_model "tutup-lapak/internal/security/model"
+	security_usecase "tutup-lapak/internal/security/usecase"
+	"tutup-lapak/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RateLimitConfig struct {
+	SecurityUsecase *security_usecase.SecurityUsecase
+}
+
+func NewRateLimitMiddleware(securityUsecase *security_usecase.SecurityUsecase) *RateLimitConfig {
+	return &RateLimitConfig{
+		SecurityUsecase: securityUsecase,
+	}
+}
+
+// LimitFailures locks out a client IP that keeps getting 4xx responses from
+// the wrapped route. scope keeps counters of different routes apart.
+func (r *RateLimitConfig) LimitFailures(scope string, policy security_model.LimitPolicy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			key := scope + ":ip:" + ctx.RealIP()
+
+			if err := r.SecurityUsecase.Check(ctx.Request().Context(), key); err != nil {
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			if err := next(ctx); err != nil {
+				return err
+			}
+
+			status := ctx.Response().Status
+			if status >= http.StatusBadRequest && status < http.StatusInternalServerError && status != http.StatusTooManyRequests {
+				// The response is already written, so a failure to count it
+				// can't be reported to the client anymore.
+				_ = r.SecurityUsecase.RegisterFailure(ctx.Request().Context(), key, policy)
+			}
+
+			return nil
+		}
+	}
+}
+
+// LimitRequests locks out a client IP that calls the wrapped route too often,
+// whatever the responses. It suits routes answering the same way whether they
+// did anything, like sending a reset code.
+func (r *RateLimitConfig) LimitRequests(scope string, policy security_model.LimitPolicy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			key := scope + ":ip:" + ctx.RealIP()
+
+			if err := r.SecurityUsecase.Check(ctx.Request().Context(), key); err != nil {
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+			if err := r.SecurityUsecase.RegisterFailure(ctx.Request().Context(), key, policy); err != nil {
+				return ctx.JSON(response.WriteErrorResponse(err))
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package dto
+
+type ProductImportQuery struct {
+	Mode string `query:"mode" validate:"omitempty,oneof=create upsert"`
+}
+
+type ProductExportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv ndjson"`
+}
+
+// ProductImportRow is a parsed row of an import, Row counts data rows from 1.
+type ProductImportRow struct {
+	Row     int
+	Payload ProductPayload
+}
+
+type ProductImportRowError struct {
+	Row   int    `json:"row"`
+	Sku   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+type ProductImportResponse struct {
+	Created int                     `json:"created"`
+	Updated int                     `json:"updated"`
+	Failed  int                     `json:"failed"`
+	Errors  []ProductImportRowError `json:"errors"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type PriceHistoryQuery struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"min=0"`
+}
+
+// PriceChangeResponse is served on a public route, it leaves out who made
+// the change.
+type PriceChangeResponse struct {
+	OldPrice   *int      `json:"oldPrice"`
+	NewPrice   int       `json:"newPrice"`
+	ScheduleID *string   `json:"scheduleId"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+// PriceSchedulePayload changes the price at startsAt and reverts it at
+// endsAt. Without endsAt the new price stays.
+type PriceSchedulePayload struct {
+	Price    int        `json:"price" validate:"required,min=100"`
+	StartsAt time.Time  `json:"startsAt" validate:"required"`
+	EndsAt   *time.Time `json:"endsAt"`
+}
+
+type PriceScheduleResponse struct {
+	ScheduleID    string     `json:"scheduleId"`
+	ProductID     string     `json:"productId"`
+	Price         int        `json:"price"`
+	PreviousPrice *int       `json:"previousPrice"`
+	StartsAt      time.Time  `json:"startsAt"`
+	EndsAt        *time.Time `json:"endsAt"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
@@ -3,41 +3,110 @@ package dto
 import "time"
 
 type ProductPayload struct {
-	Name     string `json:"name" validate:"required,min=4,max=32"`
-	Category string `json:"category" validate:"required,oneof=Food Beverage Clothes Furniture Tools"`
-	Qty      int    `json:"qty" validate:"required,number,min=1"`
-	Price    int    `json:"price" validate:"required,number,min=100"`
-	Sku      string `json:"sku" validate:"required,min=1,max=32"`
-	FileID   string `json:"fileId" validate:"required,number"`
+	Name        string   `json:"name" validate:"required,min=4,max=32"`
+	Category    string   `json:"category" validate:"required,max=64"`
+	Qty         int      `json:"qty" validate:"required,number,min=1"`
+	Price       int      `json:"price" validate:"required,number,min=100"`
+	Sku         string   `json:"sku" validate:"required,min=1,max=32"`
+	Description string   `json:"description" validate:"max=2000"`
+	FileID      string   `json:"fileId" validate:"required_without=FileIDs,omitempty,number"`
+	FileIDs     []string `json:"fileIds" validate:"omitempty,max=10,unique,dive,number"`
+}
+
+// ProductUpdatePayload is a JSON Merge Patch of a product. Only the fields
+// present in the document are validated and changed.
+type ProductUpdatePayload struct {
+	Name        *string  `json:"name" validate:"omitnil,min=4,max=32"`
+	Category    *string  `json:"category" validate:"omitnil,min=1,max=64"`
+	Qty         *int     `json:"qty" validate:"omitnil,min=0"`
+	Price       *int     `json:"price" validate:"omitnil,min=100"`
+	Sku         *string  `json:"sku" validate:"omitnil,min=1,max=32"`
+	Description *string  `json:"description" validate:"omitnil,max=2000"`
+	FileID      *string  `json:"fileId" validate:"omitnil,number"`
+	FileIDs     []string `json:"fileIds" validate:"omitnil,max=10,unique,dive,number"`
+}
+
+// ProductImagesPayload replaces the images of a product. The first file is
+// the primary image.
+type ProductImagesPayload struct {
+	FileIDs []string `json:"fileIds" validate:"required,min=1,max=10,unique,dive,number"`
+}
+
+type ProductImage struct {
+	FileID           string `json:"fileId"`
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+	IsPrimary        bool   `json:"isPrimary"`
 }
 
 type ProductGetPayload struct {
-	Limit     int     `query:"limit" validate:"omitempty,number,min=0"`
-	Offset    int     `query:"offset" validate:"omitempty,number,min=0"`
-	ProductID *string `query:"productId" validate:"omitempty,number,min=1"`
-	Sku       *string `query:"sku" validate:"omitempty,min=1"`
-	Category  *string `query:"category" validate:"omitempty,oneof=Food Beverage Clothes Furniture Tools"`
-	SortBy    *string `query:"sortBy" validate:"omitempty,sort_by"`
+	Limit     int      `query:"limit" validate:"omitempty,number,min=0"`
+	Offset    int      `query:"offset" validate:"omitempty,number,min=0"`
+	ProductID *string  `query:"productId" validate:"omitempty,number,min=1"`
+	Sku       *string  `query:"sku" validate:"omitempty,min=1"`
+	SellerID  *string  `query:"sellerId" validate:"omitempty,number,min=1"`
+	Category  []string `query:"category" validate:"omitempty,max=5,dive,min=1,max=64"`
+	MinPrice  *int     `query:"minPrice" validate:"omitnil,min=0"`
+	MaxPrice  *int     `query:"maxPrice" validate:"omitnil,min=0"`
+	InStock   *bool    `query:"inStock"`
+	// SortBy is newest, cheapest or sold-N. sold-N ranks by units sold in
+	// the last N seconds counted in whole hours: the window opens at the
+	// start of the hour holding NOW() - N, so it can run up to an hour long.
+	SortBy   *string `query:"sortBy" validate:"omitempty,sort_by"`
+	Q        *string `query:"q" validate:"omitempty,min=1,max=100"`
+	Cursor   *string `query:"cursor" validate:"omitempty,max=512"`
+	Envelope bool    `query:"envelope"`
+}
+
+// ProductCursor is the decoded form of the opaque cursor tokens. It records
+// the sort it was issued for and the sort key and ID of the boundary row.
+type ProductCursor struct {
+	SortBy   string `json:"s"`
+	Value    string `json:"v,omitempty"`
+	ID       int    `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+type ProductPageResponse struct {
+	Data       []ProductResponse `json:"data"`
+	NextCursor *string           `json:"nextCursor"`
+	PrevCursor *string           `json:"prevCursor"`
 }
 
 type ProductResponse struct {
-	ProductID        string    `json:"productId"`
-	Name             string    `json:"name"`
-	Category         string    `json:"category"`
-	Qty              int       `json:"qty"`
-	Price            int       `json:"price"`
-	Sku              string    `json:"sku"`
-	FileID           string    `json:"fileId"`
-	FileURI          string    `json:"fileUri"`
-	FileThumbnailURI string    `json:"fileThumbnailUri"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	ProductID        string                  `json:"productId"`
+	Name             string                  `json:"name"`
+	Category         string                  `json:"category"`
+	Qty              int                     `json:"qty"`
+	Price            int                     `json:"price"`
+	Sku              string                  `json:"sku"`
+	Description      string                  `json:"description"`
+	FileID           string                  `json:"fileId"`
+	FileURI          string                  `json:"fileUri"`
+	FileThumbnailURI string                  `json:"fileThumbnailUri"`
+	Images           []ProductImage          `json:"images"`
+	PriceRange       PriceRange              `json:"priceRange"`
+	AvailableQty     int                     `json:"availableQty"`
+	VariantCount     int                     `json:"variantCount"`
+	Variant          *ProductVariantResponse `json:"variant,omitempty"`
+	Version          int                     `json:"version"`
+	SoldQty          int64                   `json:"-"`
+	CreatedAt        time.Time               `json:"createdAt"`
+	UpdatedAt        time.Time               `json:"updatedAt"`
 }
 
 type ProductWithSeller struct {
 	ProductResponse
 	SellerId          string
+	BankAccountID     string
 	BankAccountName   string
 	BankAccountHolder string
 	BankAccountNumber string
+	// CategoryPath names the category of the product and every category
+	// above it.
+	CategoryPath []string
+}
+
+type ProductPurgeResponse struct {
+	Purged int64 `json:"purged"`
 }
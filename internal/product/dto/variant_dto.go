@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type ProductVariantPayload struct {
+	Sku   string  `json:"sku" validate:"required,min=1,max=32"`
+	Size  *string `json:"size" validate:"omitnil,min=1,max=64"`
+	Color *string `json:"color" validate:"omitnil,min=1,max=64"`
+	Qty   int     `json:"qty" validate:"min=0"`
+	Price int     `json:"price" validate:"required,min=100"`
+}
+
+type ProductVariantUpdatePayload struct {
+	Sku   *string `json:"sku" validate:"omitnil,min=1,max=32"`
+	Size  *string `json:"size" validate:"omitnil,min=1,max=64"`
+	Color *string `json:"color" validate:"omitnil,min=1,max=64"`
+	Qty   *int    `json:"qty" validate:"omitnil,min=0"`
+	Price *int    `json:"price" validate:"omitnil,min=100"`
+}
+
+type ProductVariantResponse struct {
+	VariantID string    `json:"variantId"`
+	ProductID string    `json:"productId"`
+	Sku       string    `json:"sku"`
+	Size      *string   `json:"size"`
+	Color     *string   `json:"color"`
+	Qty       int       `json:"qty"`
+	Price     int       `json:"price"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type PriceRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	custom_middleware "tutup-lapak/internal/middleware"
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxImportRows   = 5000
+	exportFlushRows = 100
+	// importTimeout bounds an import, which the global request timeout skips.
+	importTimeout = 5 * time.Minute
+)
+
+// importColumns are the CSV columns of an import or export, named like the
+// JSON fields of dto.ProductPayload. fileIds holds comma separated ids.
+var importColumns = []string{"name", "category", "qty", "price", "sku", "description", "fileId", "fileIds"}
+
+// ImportProducts reads a CSV (text/csv) or JSON Lines (application/x-ndjson)
+// body. Every row is validated like CreateProduct and the rows that fail are
+// listed in the response, the others are imported.
+func (h *ProductHandler) ImportProducts(ctx echo.Context) error {
+	var query dto.ProductImportQuery
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &query); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&query); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var rows []dto.ProductImportRow
+	var rowErrors []dto.ProductImportRowError
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case "text/csv":
+		rows, rowErrors, err = parseImportCSV(ctx.Request().Body)
+	case "application/x-ndjson", "application/jsonl":
+		rows, rowErrors, err = parseImportNDJSON(ctx.Request().Body)
+	default:
+		err = errors.Wrap(customErrors.ErrBadRequest, "content type must be text/csv or application/x-ndjson")
+	}
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	valid := make([]dto.ProductImportRow, 0, len(rows))
+	for _, row := range rows {
+		if err := h.validator.Struct(&row.Payload); err != nil {
+			rowErrors = append(rowErrors, dto.ProductImportRowError{Row: row.Row, Sku: row.Payload.Sku, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx.Request().Context(), importTimeout)
+	defer cancel()
+
+	result, err := h.usecase.ImportProducts(importCtx, sellerID, valid, query.Mode == "upsert")
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	result.Failed += len(rowErrors)
+	result.Errors = append(result.Errors, rowErrors...)
+	slices.SortFunc(result.Errors, func(a, b dto.ProductImportRowError) int {
+		return a.Row - b.Row
+	})
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// parseImportCSV maps the columns by the header row. Rows that cannot be
+// read into a payload are returned as row errors.
+func parseImportCSV(body io.Reader) ([]dto.ProductImportRow, []dto.ProductImportRowError, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.Wrap(customErrors.ErrBadRequest, "missing csv header")
+	}
+	for i, column := range header {
+		if !slices.Contains(importColumns, column) {
+			return nil, nil, errors.Wrapf(customErrors.ErrBadRequest, "unknown column %s", column)
+		}
+		if slices.Contains(header[:i], column) {
+			return nil, nil, errors.Wrapf(customErrors.ErrBadRequest, "duplicate column %s", column)
+		}
+	}
+
+	var rows []dto.ProductImportRow
+	var rowErrors []dto.ProductImportRowError
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if n > maxImportRows {
+			return nil, nil, errors.Wrapf(customErrors.ErrBadRequest, "an import has at most %d rows", maxImportRows)
+		}
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, nil, errors.Wrap(customErrors.ErrBadRequest, err.Error())
+			}
+			rowErrors = append(rowErrors, dto.ProductImportRowError{Row: n, Error: err.Error()})
+			continue
+		}
+
+		row := dto.ProductImportRow{Row: n}
+		if err := readImportRecord(header, record, &row.Payload); err != nil {
+			rowErrors = append(rowErrors, dto.ProductImportRowError{Row: n, Sku: row.Payload.Sku, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func readImportRecord(header, record []string, payload *dto.ProductPayload) error {
+	var numberErr error
+	atoi := func(column, value string) int {
+		if value == "" {
+			return 0
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil && numberErr == nil {
+			numberErr = fmt.Errorf("%s must be a number", column)
+		}
+		return number
+	}
+
+	for i, column := range header {
+		value := record[i]
+		switch column {
+		case "name":
+			payload.Name = value
+		case "category":
+			payload.Category = value
+		case "qty":
+			payload.Qty = atoi(column, value)
+		case "price":
+			payload.Price = atoi(column, value)
+		case "sku":
+			payload.Sku = value
+		case "description":
+			payload.Description = value
+		case "fileId":
+			payload.FileID = value
+		case "fileIds":
+			payload.FileIDs = splitQueryValues([]string{value})
+		}
+	}
+
+	return numberErr
+}
+
+// parseImportNDJSON reads one product per line and skips blank lines.
+func parseImportNDJSON(body io.Reader) ([]dto.ProductImportRow, []dto.ProductImportRowError, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []dto.ProductImportRow
+	var rowErrors []dto.ProductImportRowError
+	n := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		n++
+		if n > maxImportRows {
+			return nil, nil, errors.Wrapf(customErrors.ErrBadRequest, "an import has at most %d rows", maxImportRows)
+		}
+
+		row := dto.ProductImportRow{Row: n}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.Payload); err != nil {
+			rowErrors = append(rowErrors, dto.ProductImportRowError{Row: n, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(customErrors.ErrBadRequest, err.Error())
+	}
+
+	return rows, rowErrors, nil
+}
+
+// ExportProducts streams the caller's catalog as CSV or JSON Lines in the
+// format ImportProducts reads. The status is only sent with the first row,
+// so a failing query still gets an error response.
+func (h *ProductHandler) ExportProducts(ctx echo.Context) error {
+	var query dto.ProductExportQuery
+
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&query); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	res := ctx.Response()
+	csvWriter := csv.NewWriter(res)
+	encoder := json.NewEncoder(res)
+	flush := func() error {
+		csvWriter.Flush()
+		res.Flush()
+		return csvWriter.Error()
+	}
+
+	count := 0
+	start := func() error {
+		if query.Format == "ndjson" {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="products.ndjson"`)
+			res.WriteHeader(http.StatusOK)
+			return nil
+		}
+		res.Header().Set(echo.HeaderContentType, "text/csv")
+		res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="products.csv"`)
+		res.WriteHeader(http.StatusOK)
+		return csvWriter.Write(importColumns)
+	}
+
+	err = h.usecase.ExportProducts(ctx.Request().Context(), sellerID, func(product dto.ProductPayload) error {
+		if count == 0 {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		count++
+
+		if query.Format == "ndjson" {
+			if err := encoder.Encode(product); err != nil {
+				return err
+			}
+		} else {
+			err := csvWriter.Write([]string{
+				product.Name,
+				product.Category,
+				strconv.Itoa(product.Qty),
+				strconv.Itoa(product.Price),
+				product.Sku,
+				product.Description,
+				product.FileID,
+				strings.Join(product.FileIDs, ","),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if count%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if count == 0 {
+			return ctx.JSON(response.WriteErrorResponse(err))
+		}
+		return err
+	}
+
+	if count == 0 {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantRows   []dto.ProductImportRow
+		wantErrors []int
+		wantErr    bool
+	}{
+		{
+			name: "columns in any order",
+			body: "sku,name,qty,price,category,fileIds\n" +
+				"A-1,Kopi,3,15000,Beverage,\"1, 2\"\n",
+			wantRows: []dto.ProductImportRow{{Row: 1, Payload: dto.ProductPayload{
+				Name: "Kopi", Category: "Beverage", Qty: 3, Price: 15000, Sku: "A-1", FileIDs: []string{"1", "2"},
+			}}},
+		},
+		{
+			name: "bad rows are reported and the others kept",
+			body: "name,qty,price,sku\n" +
+				"Kopi,x,15000,A-1\n" +
+				"Teh,1\n" +
+				"Susu,2,9000,A-3\n",
+			wantRows: []dto.ProductImportRow{{Row: 3, Payload: dto.ProductPayload{
+				Name: "Susu", Qty: 2, Price: 9000, Sku: "A-3",
+			}}},
+			wantErrors: []int{1, 2},
+		},
+		{name: "missing header", body: "", wantErr: true},
+		{name: "unknown column", body: "name,colour\n", wantErr: true},
+		{name: "duplicate column", body: "name,sku,name\n", wantErr: true},
+		{name: "too many rows", body: "name\n" + strings.Repeat("Kopi\n", maxImportRows+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors, err := parseImportCSV(strings.NewReader(tt.body))
+			checkImportParse(t, rows, rowErrors, err, tt.wantRows, tt.wantErrors, tt.wantErr)
+		})
+	}
+}
+
+func TestParseImportNDJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantRows   []dto.ProductImportRow
+		wantErrors []int
+		wantErr    bool
+	}{
+		{
+			name: "blank lines are skipped",
+			body: `{"name":"Kopi","category":"Beverage","qty":3,"price":15000,"sku":"A-1","fileId":"1"}` + "\n\n" +
+				`{"name":"Teh","qty":1,"price":5000,"sku":"A-2","fileIds":["2","3"]}` + "\n",
+			wantRows: []dto.ProductImportRow{
+				{Row: 1, Payload: dto.ProductPayload{Name: "Kopi", Category: "Beverage", Qty: 3, Price: 15000, Sku: "A-1", FileID: "1"}},
+				{Row: 2, Payload: dto.ProductPayload{Name: "Teh", Qty: 1, Price: 5000, Sku: "A-2", FileIDs: []string{"2", "3"}}},
+			},
+		},
+		{
+			name: "bad lines are reported and the others kept",
+			body: `{"name":"Kopi","sku":"A-1","colour":"black"}` + "\n" +
+				`{"name":` + "\n" +
+				`{"name":"Susu","qty":"2"}` + "\n" +
+				`{"name":"Teh","sku":"A-4"}` + "\n",
+			wantRows:   []dto.ProductImportRow{{Row: 4, Payload: dto.ProductPayload{Name: "Teh", Sku: "A-4"}}},
+			wantErrors: []int{1, 2, 3},
+		},
+		{name: "too many rows", body: strings.Repeat("{}\n", maxImportRows+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors, err := parseImportNDJSON(strings.NewReader(tt.body))
+			checkImportParse(t, rows, rowErrors, err, tt.wantRows, tt.wantErrors, tt.wantErr)
+		})
+	}
+}
+
+func checkImportParse(t *testing.T, rows []dto.ProductImportRow, rowErrors []dto.ProductImportRowError, err error, wantRows []dto.ProductImportRow, wantErrors []int, wantErr bool) {
+	t.Helper()
+
+	if wantErr {
+		if !errors.Is(err, customErrors.ErrBadRequest) {
+			t.Fatalf("error = %v, want ErrBadRequest", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("rows = %+v, want %+v", rows, wantRows)
+	}
+
+	var errorRows []int
+	for _, rowError := range rowErrors {
+		errorRows = append(errorRows, rowError.Row)
+	}
+	if !reflect.DeepEqual(errorRows, wantErrors) {
+		t.Errorf("row errors on rows %v, want %v", errorRows, wantErrors)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	custom_middleware "tutup-lapak/internal/middleware"
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+func (h *ProductHandler) GetPriceHistory(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var query dto.PriceHistoryQuery
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&query); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DEFAULT_LIMIT
+	}
+
+	changes, err := h.usecase.GetPriceHistory(ctx.Request().Context(), productID, &query)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, changes)
+}
+
+func (h *ProductHandler) ListPriceSchedules(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	schedules, err := h.usecase.ListPriceSchedules(ctx.Request().Context(), productID, sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, schedules)
+}
+
+func (h *ProductHandler) CreatePriceSchedule(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var payload dto.PriceSchedulePayload
+	if err := ctx.Bind(&payload); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	schedule, err := h.usecase.CreatePriceSchedule(ctx.Request().Context(), productID, sellerID, &payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, schedule)
+}
+
+func (h *ProductHandler) CancelPriceSchedule(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	id, err := strconv.Atoi(ctx.Param("scheduleId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	schedule, err := h.usecase.CancelPriceSchedule(ctx.Request().Context(), id, productID, sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, schedule)
+}
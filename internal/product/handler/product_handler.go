@@ -1,9 +1,16 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	custom_middleware "tutup-lapak/internal/middleware"
 	"tutup-lapak/internal/product/dto"
 	"tutup-lapak/internal/product/usecase"
 	customErrors "tutup-lapak/pkg/custom-errors"
@@ -42,14 +49,17 @@ func (h *ProductHandler) CreateProduct(ctx echo.Context) error {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
-	// TODO: Get sellerID from Auth
-	sellerID := 1
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
 
 	product, err := h.usecase.CreateProduct(ctx.Request().Context(), &sellerID, &payload)
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
+	ctx.Response().Header().Set("ETag", productETag(product.Version))
 	return ctx.JSON(http.StatusOK, &product)
 }
 
@@ -60,15 +70,26 @@ func (h *ProductHandler) GetProducts(ctx echo.Context) error {
 		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
 	}
 
+	payload.Category = splitQueryValues(payload.Category)
+
 	if err := h.validator.Struct(&payload); err != nil {
 		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
+	if payload.MinPrice != nil && payload.MaxPrice != nil && *payload.MinPrice > *payload.MaxPrice {
+		err := errors.Wrap(customErrors.ErrBadRequest, "minPrice must not be greater than maxPrice")
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
 	if payload.Limit == 0 {
 		payload.Limit = DEFAULT_LIMIT
 	}
 
+	if payload.Q != nil && strings.TrimSpace(*payload.Q) == "" {
+		payload.Q = nil
+	}
+
 	if payload.SortBy != nil {
 		sec, found := h.parseSortBy(payload.SortBy)
 		if !found {
@@ -78,11 +99,37 @@ func (h *ProductHandler) GetProducts(ctx echo.Context) error {
 		payload.SortBy = sec
 	}
 
+	// the envelope carries cursors, the bare array stays for offset clients
+	if payload.Envelope || payload.Cursor != nil {
+		page, err := h.usecase.GetProductPage(ctx.Request().Context(), &payload)
+		if err != nil {
+			return ctx.JSON(response.WriteErrorResponse(err))
+		}
+
+		etag := productsETag(page.Data)
+		ctx.Response().Header().Set("ETag", etag)
+		if etagMatches(ctx.Request().Header.Get("If-None-Match"), etag) {
+			return ctx.NoContent(http.StatusNotModified)
+		}
+
+		if page.Data == nil {
+			page.Data = make([]dto.ProductResponse, 0)
+		}
+
+		return ctx.JSON(http.StatusOK, page)
+	}
+
 	products, err := h.usecase.GetProducts(ctx.Request().Context(), &payload)
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
+	etag := productsETag(*products)
+	ctx.Response().Header().Set("ETag", etag)
+	if etagMatches(ctx.Request().Header.Get("If-None-Match"), etag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
 	if len(*products) == 0 {
 		return ctx.JSON(http.StatusOK, make([]bool, 0))
 	}
@@ -90,13 +137,77 @@ func (h *ProductHandler) GetProducts(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, &products)
 }
 
+// GetProductBySku resolves one of the caller's own SKUs to its product.
+func (h *ProductHandler) GetProductBySku(ctx echo.Context) error {
+	sku := ctx.Param("sku")
+	if err := h.validator.Var(sku, "required,min=1,max=32"); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	product, err := h.usecase.GetProductBySku(ctx.Request().Context(), &sellerID, sku)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	etag := productETag(product.Version)
+	ctx.Response().Header().Set("ETag", etag)
+	if etagMatches(ctx.Request().Header.Get("If-None-Match"), etag) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
+	return ctx.JSON(http.StatusOK, &product)
+}
+
 func (h *ProductHandler) UpdateProduct(ctx echo.Context) error {
 	id, err := strconv.Atoi(ctx.Param("productId"))
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
 	}
 
-	var payload dto.ProductPayload
+	var payload dto.ProductUpdatePayload
+	if err := bindMergePatch(ctx.Request().Body, &payload); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.validator.Struct(&payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	version, err := parseIfMatch(ctx.Request().Header.Get("If-Match"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	product, err := h.usecase.UpdateProduct(ctx.Request().Context(), &id, &sellerID, version, &payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	ctx.Response().Header().Set("ETag", productETag(product.Version))
+	return ctx.JSON(http.StatusOK, &product)
+}
+
+// SetProductImages replaces the ordered images of a product. The first file
+// becomes the primary image.
+func (h *ProductHandler) SetProductImages(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var payload dto.ProductImagesPayload
 	if err := ctx.Bind(&payload); err != nil {
 		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
 	}
@@ -106,14 +217,23 @@ func (h *ProductHandler) UpdateProduct(ctx echo.Context) error {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
-	// TODO: Get sellerID from Auth
-	sellerID := 1
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	version, err := parseIfMatch(ctx.Request().Header.Get("If-Match"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
 
-	product, err := h.usecase.UpdateProduct(ctx.Request().Context(), &id, &sellerID, &payload)
+	update := dto.ProductUpdatePayload{FileIDs: payload.FileIDs}
+	product, err := h.usecase.UpdateProduct(ctx.Request().Context(), &id, &sellerID, version, &update)
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
 
+	ctx.Response().Header().Set("ETag", productETag(product.Version))
 	return ctx.JSON(http.StatusOK, &product)
 }
 
@@ -123,10 +243,63 @@ func (h *ProductHandler) DeleteProduct(ctx echo.Context) error {
 		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
 	}
 
-	// TODO: Get sellerID from Auth
-	sellerID := 1
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
 
-	err = h.usecase.DeleteProduct(ctx.Request().Context(), &id, &sellerID)
+	version, err := parseIfMatch(ctx.Request().Header.Get("If-Match"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	err = h.usecase.DeleteProduct(ctx.Request().Context(), &id, &sellerID, version)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  "OK",
+		Message: "Product is deleted",
+	})
+}
+
+func (h *ProductHandler) RestoreProduct(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	product, err := h.usecase.RestoreProduct(ctx.Request().Context(), &id, &sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	ctx.Response().Header().Set("ETag", productETag(product.Version))
+	return ctx.JSON(http.StatusOK, &product)
+}
+
+func (h *ProductHandler) PurgeProducts(ctx echo.Context) error {
+	result, err := h.usecase.PurgeProducts(ctx.Request().Context())
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (h *ProductHandler) ModerateDeleteProduct(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	err = h.usecase.ModerateDeleteProduct(ctx.Request().Context(), &id)
 	if err != nil {
 		return ctx.JSON(response.WriteErrorResponse(err))
 	}
@@ -137,6 +310,99 @@ func (h *ProductHandler) DeleteProduct(ctx echo.Context) error {
 	})
 }
 
+// splitQueryValues accepts both repeated and comma separated query values,
+// e.g. category=Food&category=Tools or category=Food,Tools.
+func splitQueryValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func productETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// productsETag identifies a page of products by the IDs and versions it
+// holds, so any change to a listed product yields a new tag.
+func productsETag(products []dto.ProductResponse) string {
+	hash := sha256.New()
+	for _, product := range products {
+		fmt.Fprintf(hash, "%s:%d\n", product.ProductID, product.Version)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// parseIfMatch returns the product version required by an If-Match header,
+// or nil when the header is absent or "*".
+func parseIfMatch(header string) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "If-Match must hold a single ETag")
+	}
+
+	// weak tags never match under the strong comparison If-Match requires
+	tag, found := strings.CutPrefix(header, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	version, err := strconv.Atoi(tag)
+	if !found || !closed || err != nil {
+		return nil, errors.Wrap(customErrors.ErrPreconditionFailed, "If-Match does not match the product")
+	}
+
+	return &version, nil
+}
+
+// etagMatches applies the weak comparison of If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bindMergePatch decodes a JSON Merge Patch (RFC 7396) document. Product
+// fields cannot be removed, so null members are rejected along with members
+// that are not part of the payload.
+func bindMergePatch(body io.Reader, payload *dto.ProductUpdatePayload) error {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, err.Error())
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, "patch must be a JSON object")
+	}
+	if len(members) == 0 {
+		return errors.Wrap(customErrors.ErrBadRequest, "patch has no fields to update")
+	}
+	for field, value := range members {
+		if string(value) == "null" {
+			return errors.Wrapf(customErrors.ErrBadRequest, "field %s cannot be removed", field)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(payload); err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, err.Error())
+	}
+
+	return nil
+}
+
 func (h *ProductHandler) parseSortBy(s *string) (*string, bool) {
 	if s == nil {
 		return nil, true
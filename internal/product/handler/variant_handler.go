@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	custom_middleware "tutup-lapak/internal/middleware"
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+func (h *ProductHandler) ListVariants(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	variants, err := h.usecase.ListVariants(ctx.Request().Context(), productID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, variants)
+}
+
+func (h *ProductHandler) CreateVariant(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var payload dto.ProductVariantPayload
+	if err := ctx.Bind(&payload); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	variant, err := h.usecase.CreateVariant(ctx.Request().Context(), productID, sellerID, &payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, variant)
+}
+
+func (h *ProductHandler) UpdateVariant(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	id, err := strconv.Atoi(ctx.Param("variantId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	var payload dto.ProductVariantUpdatePayload
+	if err := ctx.Bind(&payload); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrBadRequest))
+	}
+
+	if err := h.validator.Struct(&payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	variant, err := h.usecase.UpdateVariant(ctx.Request().Context(), id, productID, sellerID, &payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, variant)
+}
+
+func (h *ProductHandler) DeleteVariant(ctx echo.Context) error {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	id, err := strconv.Atoi(ctx.Param("variantId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.usecase.DeleteVariant(ctx.Request().Context(), id, productID, sellerID); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  "OK",
+		Message: "Variant is deleted",
+	})
+}
@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model"
+)
+
+func ToPriceChangeResponse(change model.ProductPriceChange) dto.PriceChangeResponse {
+	return dto.PriceChangeResponse{
+		OldPrice:   change.OldPrice,
+		NewPrice:   change.NewPrice,
+		ScheduleID: optionalID(change.ScheduleID),
+		ChangedAt:  change.CreatedAt,
+	}
+}
+
+func ToPriceChangeResponses(changes []model.ProductPriceChange) []dto.PriceChangeResponse {
+	responses := make([]dto.PriceChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		responses = append(responses, ToPriceChangeResponse(change))
+	}
+	return responses
+}
+
+func ToPriceScheduleResponse(schedule model.ProductPriceSchedule) dto.PriceScheduleResponse {
+	return dto.PriceScheduleResponse{
+		ScheduleID:    strconv.Itoa(schedule.ID),
+		ProductID:     strconv.Itoa(schedule.ProductID),
+		Price:         schedule.Price,
+		PreviousPrice: schedule.PreviousPrice,
+		StartsAt:      schedule.StartsAt,
+		EndsAt:        schedule.EndsAt,
+		Status:        schedule.Status,
+		CreatedAt:     schedule.CreatedAt,
+		UpdatedAt:     schedule.UpdatedAt,
+	}
+}
+
+func ToPriceScheduleResponses(schedules []model.ProductPriceSchedule) []dto.PriceScheduleResponse {
+	responses := make([]dto.PriceScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		responses = append(responses, ToPriceScheduleResponse(schedule))
+	}
+	return responses
+}
+
+func optionalID(id *int) *string {
+	if id == nil {
+		return nil
+	}
+	value := strconv.Itoa(*id)
+	return &value
+}
@@ -0,0 +1,29 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model"
+)
+
+func ToProductVariantResponse(variant model.ProductVariant) dto.ProductVariantResponse {
+	return dto.ProductVariantResponse{
+		VariantID: strconv.Itoa(variant.ID),
+		ProductID: strconv.Itoa(variant.ProductID),
+		Sku:       variant.Sku,
+		Size:      variant.Size,
+		Color:     variant.Color,
+		Qty:       variant.Qty,
+		Price:     variant.Price,
+		CreatedAt: variant.CreatedAt,
+		UpdatedAt: variant.UpdatedAt,
+	}
+}
+
+func ToProductVariantResponses(variants []model.ProductVariant) []dto.ProductVariantResponse {
+	responses := make([]dto.ProductVariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		responses = append(responses, ToProductVariantResponse(variant))
+	}
+	return responses
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	PriceSchedulePending   = "pending"
+	PriceScheduleActive    = "active"
+	PriceScheduleCompleted = "completed"
+	PriceScheduleExpired   = "expired"
+	PriceScheduleCanceled  = "canceled"
+)
+
+type ProductPriceChange struct {
+	ID         int
+	ProductID  int
+	OldPrice   *int
+	NewPrice   int
+	ChangedBy  *int
+	ScheduleID *int
+	CreatedAt  time.Time
+}
+
+type ProductPriceSchedule struct {
+	ID            int
+	ProductID     int
+	Price         int
+	PreviousPrice *int
+	StartsAt      time.Time
+	EndsAt        *time.Time
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
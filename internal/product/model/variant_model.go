@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type ProductVariant struct {
+	ID        int
+	ProductID int
+	Sku       string
+	Size      *string
+	Color     *string
+	Qty       int
+	Price     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
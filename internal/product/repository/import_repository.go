@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	queryImportProduct = `
+	INSERT INTO products (seller_id, name, category_id, qty, price, sku, description, file_id)
+	VALUES (@sellerID, @name, (SELECT id FROM categories WHERE name = @category), @qty, @price, @sku, @description, @fileID)
+	RETURNING id::TEXT, TRUE;`
+	// queryUpsertProduct keeps the qty and price of a product with variants,
+	// which are derived from its variants
+	queryUpsertProduct = `
+	INSERT INTO products (seller_id, name, category_id, qty, price, sku, description, file_id)
+	VALUES (@sellerID, @name, (SELECT id FROM categories WHERE name = @category), @qty, @price, @sku, @description, @fileID)
+	ON CONFLICT (seller_id, sku) WHERE deleted_at IS NULL DO UPDATE
+	SET
+		name = EXCLUDED.name,
+		category_id = EXCLUDED.category_id,
+		qty = CASE WHEN EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.deleted_at IS NULL) THEN products.qty ELSE EXCLUDED.qty END,
+		price = CASE WHEN EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.deleted_at IS NULL) THEN products.price ELSE EXCLUDED.price END,
+		description = EXCLUDED.description,
+		file_id = EXCLUDED.file_id
+	RETURNING id::TEXT, xmax = 0;`
+	queryExportProducts = `
+	SELECT
+		p.name,
+		c.name category,
+		p.qty,
+		p.price,
+		p.sku,
+		p.description,
+		p.file_id::TEXT,
+		ARRAY(SELECT pf.file_id::TEXT FROM product_files pf WHERE pf.product_id = p.id ORDER BY pf.position) file_ids
+	FROM products p
+	JOIN categories c ON c.id = p.category_id
+	WHERE p.seller_id = @sellerID AND p.deleted_at IS NULL
+	ORDER BY p.id;`
+)
+
+// ImportProducts writes payloads, one chunk of an import, in a transaction of
+// their own. A failing row is rolled back to its savepoint and reported in
+// rowErrs without aborting the others, created tells whether a row inserted a
+// product or updated one by sku.
+func (r *ProductRepo) ImportProducts(ctx context.Context, sellerID int, payloads []dto.ProductPayload, upsert bool) (created []bool, rowErrs []error, err error) {
+	query := queryImportProduct
+	if upsert {
+		query = queryUpsertProduct
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, customErrors.HandlePgError(err, "could not begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setPriceChangedBy(ctx, tx, sellerID); err != nil {
+		return nil, nil, err
+	}
+
+	created = make([]bool, len(payloads))
+	rowErrs = make([]error, len(payloads))
+	for i, payload := range payloads {
+		inserted, err := importProduct(ctx, tx, query, sellerID, payload)
+		if err != nil {
+			rowErrs[i] = err
+			continue
+		}
+		created[i] = inserted
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return created, rowErrs, nil
+}
+
+func importProduct(ctx context.Context, tx pgx.Tx, query string, sellerID int, payload dto.ProductPayload) (bool, error) {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return false, customErrors.HandlePgError(err, "could not begin savepoint")
+	}
+	defer savepoint.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"sellerID":    sellerID,
+		"name":        payload.Name,
+		"category":    payload.Category,
+		"qty":         payload.Qty,
+		"price":       payload.Price,
+		"sku":         payload.Sku,
+		"description": payload.Description,
+		"fileID":      payload.FileID,
+	}
+
+	var productID string
+	var inserted bool
+	if err := savepoint.QueryRow(ctx, query, args).Scan(&productID, &inserted); err != nil {
+		return false, customErrors.HandlePgConstraintError(err, "failed import product", productConstraints)
+	}
+
+	if err := replaceProductFiles(ctx, savepoint, productID, payload.FileIDs); err != nil {
+		return false, err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return false, customErrors.HandlePgError(err, "could not release savepoint")
+	}
+
+	return inserted, nil
+}
+
+// ExportProducts calls fn for every product of sellerID while the rows are
+// read, so the catalog is never held in memory.
+func (r *ProductRepo) ExportProducts(ctx context.Context, sellerID int, fn func(dto.ProductPayload) error) error {
+	rows, err := r.db.Query(ctx, queryExportProducts, pgx.NamedArgs{"sellerID": sellerID})
+	if err != nil {
+		return customErrors.HandlePgError(err, "failed export products")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product dto.ProductPayload
+		err := rows.Scan(
+			&product.Name,
+			&product.Category,
+			&product.Qty,
+			&product.Price,
+			&product.Sku,
+			&product.Description,
+			&product.FileID,
+			&product.FileIDs,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	querySetPriceChangedBy = "SELECT set_config('app.price_changed_by', @changedBy::TEXT, TRUE);"
+	queryGetPriceHistory   = `
+	SELECT h.id, h.product_id, h.old_price, h.new_price, h.changed_by, h.schedule_id, h.created_at
+	FROM product_price_history h
+	WHERE h.product_id = @productID
+	ORDER BY h.created_at DESC, h.id DESC
+	LIMIT @limit
+	OFFSET @offset;`
+	queryCreatePriceSchedule = `
+	INSERT INTO product_price_schedules (product_id, price, starts_at, ends_at, created_by)
+	SELECT p.id, @price, @startsAt, @endsAt, p.seller_id
+	FROM products p
+	WHERE p.id = @productID AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	RETURNING id, product_id, price, previous_price, starts_at, ends_at, status, created_at, updated_at;`
+	queryListPriceSchedules = `
+	SELECT s.id, s.product_id, s.price, s.previous_price, s.starts_at, s.ends_at, s.status, s.created_at, s.updated_at
+	FROM product_price_schedules s
+	JOIN products p ON p.id = s.product_id
+	WHERE s.product_id = @productID AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	ORDER BY s.starts_at DESC, s.id DESC;`
+	// queryCancelPriceSchedule drops a pending schedule and ends an active
+	// one now, so the worker reverts its price
+	queryCancelPriceSchedule = `
+	UPDATE product_price_schedules s
+	SET
+		status = CASE WHEN s.status = 'pending' THEN 'canceled' ELSE s.status END,
+		ends_at = CASE WHEN s.status = 'active' THEN GREATEST(NOW(), s.starts_at + INTERVAL '1 second') ELSE s.ends_at END
+	FROM products p
+	WHERE s.id = @ID AND s.product_id = @productID AND s.status IN ('pending', 'active')
+		AND p.id = s.product_id AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	RETURNING s.id, s.product_id, s.price, s.previous_price, s.starts_at, s.ends_at, s.status, s.created_at, s.updated_at;`
+	// queryGetDuePriceSchedule takes the earliest due start or end, ends
+	// first on a tie so back to back schedules apply in order
+	queryGetDuePriceSchedule = `
+	SELECT s.id, s.product_id, s.price, s.previous_price, s.starts_at, s.ends_at, s.status, s.created_at, s.updated_at,
+		COALESCE(s.ends_at <= NOW(), FALSE) ended
+	FROM product_price_schedules s
+	WHERE (s.status = 'pending' AND s.starts_at <= NOW()) OR (s.status = 'active' AND s.ends_at <= NOW())
+	ORDER BY CASE WHEN s.status = 'active' THEN s.ends_at ELSE s.starts_at END, s.status = 'pending'
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED;`
+	queryLockScheduledProduct = `
+	SELECT
+		p.price,
+		p.deleted_at IS NULL,
+		EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id AND v.deleted_at IS NULL)
+	FROM products p
+	WHERE p.id = @productID
+	FOR UPDATE;`
+	querySetPriceSchedule = `
+	SELECT
+		set_config('app.price_schedule_id', s.id::TEXT, TRUE),
+		set_config('app.price_changed_by', COALESCE(s.created_by::TEXT, ''), TRUE)
+	FROM product_price_schedules s
+	WHERE s.id = @ID;`
+	queryApplySchedulePrice  = "UPDATE products SET price = @price WHERE id = @productID;"
+	queryUpdatePriceSchedule = `
+	UPDATE product_price_schedules
+	SET status = @status, previous_price = COALESCE(@previousPrice, previous_price)
+	WHERE id = @ID;`
+)
+
+// setPriceChangedBy records changedBy as the author of the price changes
+// made in tx.
+func setPriceChangedBy(ctx context.Context, tx pgx.Tx, changedBy int) error {
+	_, err := tx.Exec(ctx, querySetPriceChangedBy, pgx.NamedArgs{"changedBy": strconv.Itoa(changedBy)})
+	if err != nil {
+		return customErrors.HandlePgError(err, "failed set price author")
+	}
+	return nil
+}
+
+// withPriceChangedBy runs fn in a transaction whose price changes are
+// recorded as made by changedBy.
+func (r *ProductRepo) withPriceChangedBy(ctx context.Context, changedBy int, fn func(pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return customErrors.HandlePgError(err, "could not begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setPriceChangedBy(ctx, tx, changedBy); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ProductRepo) GetPriceHistory(ctx context.Context, productID int, query *dto.PriceHistoryQuery) ([]model.ProductPriceChange, error) {
+	args := pgx.NamedArgs{"productID": productID, "limit": query.Limit, "offset": query.Offset}
+
+	rows, err := r.db.Query(ctx, queryGetPriceHistory, args)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get price history")
+	}
+	defer rows.Close()
+
+	var changes []model.ProductPriceChange
+	for rows.Next() {
+		var change model.ProductPriceChange
+		err := rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.ChangedBy,
+			&change.ScheduleID,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// CreatePriceSchedule schedules a price for a product owned by sellerID.
+func (r *ProductRepo) CreatePriceSchedule(ctx context.Context, productID, sellerID int, payload *dto.PriceSchedulePayload) (model.ProductPriceSchedule, error) {
+	args := pgx.NamedArgs{
+		"productID": productID,
+		"sellerID":  sellerID,
+		"price":     payload.Price,
+		"startsAt":  payload.StartsAt,
+		"endsAt":    payload.EndsAt,
+	}
+
+	schedule, err := scanPriceSchedule(r.db.QueryRow(ctx, queryCreatePriceSchedule, args))
+	if err != nil {
+		return model.ProductPriceSchedule{}, customErrors.HandlePgConstraintError(err, "failed create price schedule", productConstraints)
+	}
+
+	return schedule, nil
+}
+
+func (r *ProductRepo) ListPriceSchedules(ctx context.Context, productID, sellerID int) ([]model.ProductPriceSchedule, error) {
+	args := pgx.NamedArgs{"productID": productID, "sellerID": sellerID}
+
+	rows, err := r.db.Query(ctx, queryListPriceSchedules, args)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get price schedules")
+	}
+	defer rows.Close()
+
+	var schedules []model.ProductPriceSchedule
+	for rows.Next() {
+		schedule, err := scanPriceSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (r *ProductRepo) CancelPriceSchedule(ctx context.Context, ID, productID, sellerID int) (model.ProductPriceSchedule, error) {
+	args := pgx.NamedArgs{"ID": ID, "productID": productID, "sellerID": sellerID}
+
+	schedule, err := scanPriceSchedule(r.db.QueryRow(ctx, queryCancelPriceSchedule, args))
+	if err != nil {
+		return model.ProductPriceSchedule{}, customErrors.HandlePgError(err, "failed cancel price schedule")
+	}
+
+	return schedule, nil
+}
+
+// ApplyNextPriceSchedule starts or ends the earliest due schedule and
+// reports whether there was one. Due schedules are locked with SKIP LOCKED,
+// so several workers can run at once.
+//
+// A schedule whose window passed before it started expires untouched, one of
+// a deleted product or a product with variants is canceled. Ending a schedule
+// leaves the price alone when it was changed since the schedule started.
+func (r *ProductRepo) ApplyNextPriceSchedule(ctx context.Context) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, customErrors.HandlePgError(err, "could not begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	var schedule model.ProductPriceSchedule
+	var ended bool
+	err = tx.QueryRow(ctx, queryGetDuePriceSchedule).Scan(
+		&schedule.ID,
+		&schedule.ProductID,
+		&schedule.Price,
+		&schedule.PreviousPrice,
+		&schedule.StartsAt,
+		&schedule.EndsAt,
+		&schedule.Status,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+		&ended,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, customErrors.HandlePgError(err, "failed get due price schedule")
+	}
+
+	var currentPrice int
+	var available, hasVariants bool
+	err = tx.QueryRow(ctx, queryLockScheduledProduct, pgx.NamedArgs{"productID": schedule.ProductID}).Scan(&currentPrice, &available, &hasVariants)
+	if err != nil {
+		return false, customErrors.HandlePgError(err, "failed lock scheduled product")
+	}
+
+	status, newPrice, previousPrice := model.PriceScheduleCompleted, (*int)(nil), (*int)(nil)
+	switch {
+	case schedule.Status == model.PriceScheduleActive:
+		if !hasVariants && currentPrice == schedule.Price && schedule.PreviousPrice != nil {
+			newPrice = schedule.PreviousPrice
+		}
+	case ended:
+		status = model.PriceScheduleExpired
+	case !available || hasVariants:
+		status = model.PriceScheduleCanceled
+	default:
+		newPrice, previousPrice = &schedule.Price, &currentPrice
+		if schedule.EndsAt != nil {
+			status = model.PriceScheduleActive
+		}
+	}
+
+	if newPrice != nil && *newPrice != currentPrice {
+		if _, err := tx.Exec(ctx, querySetPriceSchedule, pgx.NamedArgs{"ID": schedule.ID}); err != nil {
+			return false, customErrors.HandlePgError(err, "failed set price schedule")
+		}
+		args := pgx.NamedArgs{"productID": schedule.ProductID, "price": *newPrice}
+		if _, err := tx.Exec(ctx, queryApplySchedulePrice, args); err != nil {
+			return false, customErrors.HandlePgError(err, "failed apply scheduled price")
+		}
+	}
+
+	args := pgx.NamedArgs{"ID": schedule.ID, "status": status, "previousPrice": previousPrice}
+	if _, err := tx.Exec(ctx, queryUpdatePriceSchedule, args); err != nil {
+		return false, customErrors.HandlePgError(err, "failed update price schedule")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func scanPriceSchedule(row pgx.Row) (model.ProductPriceSchedule, error) {
+	var schedule model.ProductPriceSchedule
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.ProductID,
+		&schedule.Price,
+		&schedule.PreviousPrice,
+		&schedule.StartsAt,
+		&schedule.EndsAt,
+		&schedule.Status,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	return schedule, err
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 	"tutup-lapak/internal/product/dto"
 	customErrors "tutup-lapak/pkg/custom-errors"
+	"unicode"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,137 +23,239 @@ func NewProductRepo(db *pgxpool.Pool) *ProductRepo {
 	}
 }
 
+// productConstraints maps unique indexes of products to conflict messages.
+var productConstraints = map[string]string{
+	"idx_products_seller_id_sku":           "sku already exists",
+	"idx_product_variants_product_id_sku":  "variant sku already exists",
+	"excl_product_price_schedules_overlap": "price schedule overlaps another schedule",
+}
+
 const (
 	queryCreateProduct = `
 	WITH product as (
-		INSERT INTO products (seller_id, name, category, qty, price, sku, file_id)
-		VALUES (@sellerID, @name, @category, @qty, @price, @sku, @fileID)
-		RETURNING id::TEXT id, name, category, qty, price, sku, created_at, updated_at, file_id
+		INSERT INTO products (seller_id, name, category_id, qty, price, sku, description, file_id)
+		VALUES (@sellerID, @name, (SELECT id FROM categories WHERE name = @category), @qty, @price, @sku, @description, @fileID)
+		RETURNING id::TEXT id, name, category_id, qty, price, sku, description, version, created_at, updated_at, file_id
 	)
 	SELECT
 		p.id,
 		p.name,
-		p.category,
+		c.name category,
 		p.qty,
 		p.price,
 		p.sku,
+		p.description,
+		p.version,
 		p.created_at,
 		p.updated_at,
 		f.id::TEXT file_id,
 		f.uri file_uri,
 		f.thumbnail_uri file_thumbnail_uri
 	FROM product p
-	JOIN files f ON f.id = p.file_id;`
+	JOIN files f ON f.id = p.file_id
+	JOIN categories c ON c.id = p.category_id;`
 	queryUpdateProduct = `
 	WITH product as (
 		UPDATE products 
 		SET 
-			name = @name,
-			category = @category,
-			qty = @qty,
-			price = @price,
-			sku = @sku,
-			file_id = @fileID::BIGINT
+			name = COALESCE(@name, name),
+			category_id = COALESCE((SELECT id FROM categories WHERE name = @category::TEXT), category_id),
+			qty = COALESCE(@qty, qty),
+			price = COALESCE(@price, price),
+			sku = COALESCE(@sku, sku),
+			description = COALESCE(@description, description),
+			file_id = COALESCE(@fileID::BIGINT, file_id)
 		WHERE
-			id = @ID::BIGINT AND seller_id = @sellerID
-		RETURNING id::TEXT id, name, category, qty, price, sku, updated_at, created_at, file_id
+			id = @ID::BIGINT AND seller_id = @sellerID AND deleted_at IS NULL
+			AND (@version::INT IS NULL OR version = @version::INT)
+		RETURNING id::TEXT id, name, category_id, qty, price, sku, description, version, updated_at, created_at, file_id
 	)
 	SELECT
 		p.id,
 		p.name,
-		p.category,
+		c.name category,
 		p.qty,
 		p.price,
 		p.sku,
+		p.description,
+		p.version,
 		p.created_at,
 		p.updated_at,
 		f.id::TEXT file_id,
 		f.uri file_uri,
 		f.thumbnail_uri file_thumbnail_uri
 	FROM product p
-	JOIN files f ON f.id = p.file_id;`
-	queryDeleteProductFromPivot    = "DELETE FROM pivot_purchase_products WHERE product_id = @ID;"
-	queryDeleteProductFromProducts = "DELETE FROM products WHERE id = @ID AND seller_id = @sellerID;"
-	queryGetProducts               = `
+	JOIN files f ON f.id = p.file_id
+	JOIN categories c ON c.id = p.category_id;`
+	queryRestoreProduct = `
+	WITH product as (
+		UPDATE products
+		SET deleted_at = NULL
+		WHERE id = @ID::BIGINT AND seller_id = @sellerID AND deleted_at >= @deletedAfter AND moderated_at IS NULL
+		RETURNING id::TEXT id, name, category_id, qty, price, sku, description, version, updated_at, created_at, file_id
+	)
+	SELECT
+		p.id,
+		p.name,
+		c.name category,
+		p.qty,
+		p.price,
+		p.sku,
+		p.description,
+		p.version,
+		p.created_at,
+		p.updated_at,
+		f.id::TEXT file_id,
+		f.uri file_uri,
+		f.thumbnail_uri file_thumbnail_uri
+	FROM product p
+	JOIN files f ON f.id = p.file_id
+	JOIN categories c ON c.id = p.category_id;`
+	queryDeleteProduct      = "UPDATE products SET deleted_at = NOW() WHERE id = @ID AND (@sellerID::BIGINT IS NULL OR seller_id = @sellerID) AND (@version::INT IS NULL OR version = @version::INT) AND deleted_at IS NULL;"
+	queryModerateProduct    = "UPDATE products SET deleted_at = COALESCE(deleted_at, NOW()), moderated_at = NOW() WHERE id = @ID AND moderated_at IS NULL;"
+	queryPurgeProducts      = "DELETE FROM products p WHERE p.deleted_at < @deletedBefore AND NOT EXISTS (SELECT 1 FROM pivot_purchase_products ppp WHERE ppp.product_id = p.id);"
+	queryDeleteProductFiles = "DELETE FROM product_files WHERE product_id = @productID::BIGINT;"
+	queryInsertProductFiles = `
+	INSERT INTO product_files (product_id, file_id, position)
+	SELECT @productID::BIGINT, f.file_id, f.ordinality - 1
+	FROM unnest(@fileIDs::TEXT[]::BIGINT[]) WITH ORDINALITY f(file_id, ordinality);`
+	queryGetProductImages = `
+	SELECT pf.product_id::TEXT, f.id::TEXT, f.uri, f.thumbnail_uri, pf.position
+	FROM product_files pf
+	JOIN files f ON f.id = pf.file_id
+	WHERE pf.product_id = ANY(@productIDs::TEXT[]::BIGINT[])
+	ORDER BY pf.product_id, pf.position;`
+	queryGetProductFileIDs = `
+	SELECT pf.file_id::TEXT
+	FROM product_files pf
+	JOIN products p ON p.id = pf.product_id
+	WHERE pf.product_id = @ID AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	ORDER BY pf.position;`
+	queryGetProductVersion = "SELECT version FROM products WHERE id = @ID AND (@sellerID::BIGINT IS NULL OR seller_id = @sellerID) AND deleted_at IS NULL;"
+	queryGetProducts       = `
 	SELECT
 		p.id::TEXT id,
 		p.name,
-		p.category,
+		c.name category,
 		p.qty,
 		p.price,
 		p.sku,
+		p.description,
+		p.version,
 		p.updated_at,
 		p.created_at,
 		f.id::TEXT file_id,
 		f.uri file_uri,
-		f.thumbnail_uri file_thumbnail_uri
+		f.thumbnail_uri file_thumbnail_uri,
+		%s sold_qty
 	FROM products p
 	JOIN files f ON f.id = p.file_id
-	WHERE
-		(@productID::BIGINT IS NULL OR p.id = @productID::BIGINT)
-		AND (@sku::TEXT IS NULL OR p.sku = @sku::TEXT)
-		AND (@category::enum_product_categories IS NULL OR p.category = @category::enum_product_categories)
-		AND (COALESCE(@sortBy::TEXT, '') !~ '^[0-9]+$'
-			OR (COALESCE(@sortBy::TEXT, '') ~ '^[0-9]+$' AND p.id IN (
-				SELECT DISTINCT ON (ppp.product_id) ppp.product_id
-				FROM pivot_purchase_products ppp
-				WHERE ppp.created_at >= NOW() - (@sortBy::TEXT || ' seconds')::INTERVAL
-				ORDER BY ppp.product_id, ppp.created_at DESC
-				LIMIT @limit
-				OFFSET @offset
-				))
-			)
-	ORDER BY
-		CASE 
-			WHEN @sortBy::TEXT = 'newest' THEN GREATEST(p.created_at, p.updated_at)
-		END DESC,
-		CASE 
-			WHEN @sortBy::TEXT = 'cheapest' THEN p.price 
-		END ASC
+	JOIN categories c ON c.id = p.category_id
+	%s
+	%s
+	ORDER BY %s
 	LIMIT @limit
 	OFFSET @offset;`
+	// queryCategoryTreeCondition matches the categories requested by name
+	// and every category below them
+	queryCategoryTreeCondition = `p.category_id IN (
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories
+			WHERE name = ANY(@category::TEXT[])
+			UNION
+			SELECT child.id FROM categories child
+			JOIN tree t ON child.parent_id = t.id
+		)
+		SELECT id FROM tree
+	)`
+	queryGetProductBySku = `
+	SELECT
+		p.id::TEXT id,
+		p.name,
+		c.name category,
+		p.qty,
+		p.price,
+		p.sku,
+		p.description,
+		p.version,
+		p.created_at,
+		p.updated_at,
+		f.id::TEXT file_id,
+		f.uri file_uri,
+		f.thumbnail_uri file_thumbnail_uri
+	FROM products p
+	JOIN files f ON f.id = p.file_id
+	JOIN categories c ON c.id = p.category_id
+	WHERE p.seller_id = @sellerID AND p.sku = @sku AND p.deleted_at IS NULL;`
 	queryGetProductsByIds = `
 	SELECT
 		p.id::TEXT id,
 		p.name,
-		p.category,
+		c.name category,
 		p.qty,
 		p.price,
 		p.sku,
+		p.description,
+		p.version,
 		p.updated_at,
 		p.created_at,
 		f.id::TEXT file_id,
 		f.uri file_uri,
 		f.thumbnail_uri file_thumbnail_uri,
 		s.id::TEXT seller_id,
-		s.bank_account_name seller_bank_account_name,
-		s.bank_account_holder seller_bank_account_holder,
-		s.bank_account_number seller_bank_account_number
+		COALESCE(ba.id::TEXT, '') seller_bank_account_id,
+		COALESCE(ba.bank_account_name, s.bank_account_name, '') seller_bank_account_name,
+		COALESCE(ba.bank_account_holder, s.bank_account_holder, '') seller_bank_account_holder,
+		COALESCE(ba.bank_account_number, s.bank_account_number, '') seller_bank_account_number,
+		ARRAY(
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id, name FROM categories WHERE id = p.category_id
+				UNION
+				SELECT parent.id, parent.parent_id, parent.name FROM categories parent
+				JOIN ancestors a ON parent.id = a.parent_id
+			)
+			SELECT name FROM ancestors
+		) category_path
 	FROM products p
 	JOIN files f ON f.id = p.file_id
+	JOIN categories c ON c.id = p.category_id
 	JOIN sellers s ON s.id = p.seller_id
-	WHERE p.id IN (%s);`
+	LEFT JOIN seller_bank_accounts ba ON ba.seller_id = s.id AND ba.is_default
+	WHERE p.id IN (%s) AND p.deleted_at IS NULL;`
 )
 
 func (r *ProductRepo) CreateProduct(ctx context.Context, sellerID *int, payload *dto.ProductPayload) (*dto.ProductResponse, error) {
 	var product dto.ProductResponse
 	args := pgx.NamedArgs{
-		"sellerID": &sellerID,
-		"name":     &payload.Name,
-		"category": &payload.Category,
-		"qty":      &payload.Qty,
-		"price":    &payload.Price,
-		"sku":      &payload.Sku,
-		"fileID":   &payload.FileID,
+		"sellerID":    &sellerID,
+		"name":        &payload.Name,
+		"category":    &payload.Category,
+		"qty":         &payload.Qty,
+		"price":       &payload.Price,
+		"sku":         &payload.Sku,
+		"description": &payload.Description,
+		"fileID":      &payload.FileID,
 	}
 
-	err := r.db.QueryRow(ctx, queryCreateProduct, args).Scan(
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "could not begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setPriceChangedBy(ctx, tx, *sellerID); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, queryCreateProduct, args).Scan(
 		&product.ProductID,
 		&product.Name,
 		&product.Category,
 		&product.Qty,
 		&product.Price,
 		&product.Sku,
+		&product.Description,
+		&product.Version,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 		&product.FileID,
@@ -159,23 +263,143 @@ func (r *ProductRepo) CreateProduct(ctx context.Context, sellerID *int, payload
 		&product.FileThumbnailURI,
 	)
 	if err != nil {
-		return nil, customErrors.HandlePgError(err, "failed create product")
+		return nil, customErrors.HandlePgConstraintError(err, "failed create product", productConstraints)
+	}
+
+	if err := replaceProductFiles(ctx, tx, product.ProductID, payload.FileIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	if err := r.attachRelations(ctx, &product); err != nil {
+		return nil, err
 	}
 
 	return &product, nil
 }
 
-func (r *ProductRepo) GetProducts(ctx context.Context, payload *dto.ProductGetPayload) (*[]dto.ProductResponse, error) {
+// buildGetProductsQuery only compiles the filters present in payload into
+// the WHERE clause, so the planner can use the matching indexes. A non-nil
+// cursor replaces the offset with a keyset condition on the sort key.
+func buildGetProductsQuery(payload *dto.ProductGetPayload, cursor *dto.ProductCursor) (string, pgx.NamedArgs) {
 	args := pgx.NamedArgs{
-		"limit":     &payload.Limit,
-		"offset":    &payload.Offset,
-		"productID": &payload.ProductID,
-		"category":  &payload.Category,
-		"sku":       &payload.Sku,
-		"sortBy":    &payload.SortBy,
+		"limit":  &payload.Limit,
+		"offset": &payload.Offset,
+	}
+	conditions := []string{"p.deleted_at IS NULL"}
+
+	if payload.ProductID != nil {
+		conditions = append(conditions, "p.id = @productID::BIGINT")
+		args["productID"] = payload.ProductID
 	}
+	if payload.SellerID != nil {
+		conditions = append(conditions, "p.seller_id = @sellerID::BIGINT")
+		args["sellerID"] = payload.SellerID
+	}
+	if payload.Sku != nil {
+		conditions = append(conditions, "p.sku = @sku::TEXT")
+		args["sku"] = payload.Sku
+	}
+	if len(payload.Category) > 0 {
+		conditions = append(conditions, queryCategoryTreeCondition)
+		args["category"] = payload.Category
+	}
+	if payload.MinPrice != nil {
+		conditions = append(conditions, "p.price >= @minPrice")
+		args["minPrice"] = payload.MinPrice
+	}
+	if payload.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= @maxPrice")
+		args["maxPrice"] = payload.MaxPrice
+	}
+	if payload.InStock != nil {
+		if *payload.InStock {
+			conditions = append(conditions, "p.qty > 0")
+		} else {
+			conditions = append(conditions, "p.qty <= 0")
+		}
+	}
+
+	// sortKey orders the page ahead of p.id, which breaks ties and keeps
+	// keyset pages stable
+	sortKey, sortType, descending := "", "", true
+	soldColumn, join := "0", ""
+	if payload.SortBy != nil {
+		switch *payload.SortBy {
+		case "newest":
+			sortKey, sortType = "GREATEST(p.created_at, p.updated_at)", "TIMESTAMPTZ"
+		case "cheapest":
+			sortKey, sortType, descending = "p.price", "INT", false
+		default:
+			// sold-N, the handler strips the prefix and leaves the seconds.
+			// Sales are rolled up hourly, so the window starts at the bucket
+			// holding NOW() - N and may count up to an hour more, see
+			// ProductGetPayload.SortBy.
+			soldColumn, join = "sales.sold_qty", `JOIN (
+		SELECT psh.product_id, SUM(psh.qty) sold_qty
+		FROM product_sales_hourly psh
+		WHERE psh.bucket >= date_trunc('hour', NOW() - (@soldSeconds::TEXT || ' seconds')::INTERVAL)
+		GROUP BY psh.product_id
+	) sales ON sales.product_id = p.id`
+			sortKey, sortType = "sales.sold_qty", "BIGINT"
+			args["soldSeconds"] = payload.SortBy
+		}
+	}
+
+	if cursor != nil {
+		// rows after the cursor in page order, or before it when paging back
+		operator := ">"
+		if descending != cursor.Backward {
+			operator = "<"
+		}
+		if sortKey == "" {
+			conditions = append(conditions, fmt.Sprintf("p.id %s @cursorID::BIGINT", operator))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s, p.id) %s (@cursorValue::TEXT::%s, @cursorID::BIGINT)", sortKey, operator, sortType))
+			args["cursorValue"] = cursor.Value
+		}
+		args["cursorID"] = cursor.ID
+
+		if cursor.Backward {
+			descending = !descending
+		}
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	var orderBy []string
+	if sortKey != "" {
+		orderBy = append(orderBy, sortKey+" "+direction)
+	}
+
+	if payload.Q != nil {
+		tsQuery := toPrefixTsQuery(payload.Q)
+		conditions = append(conditions, `(p.search_vector @@ to_tsquery('simple', @tsQuery::TEXT) OR @q::TEXT <% p.name)`)
+		orderBy = append(orderBy, `COALESCE(ts_rank(p.search_vector, to_tsquery('simple', @tsQuery::TEXT)), 0)
+			+ word_similarity(@q::TEXT, p.name) DESC`)
+		args["q"] = payload.Q
+		args["tsQuery"] = tsQuery
+	}
+	orderBy = append(orderBy, "p.id "+direction)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, "\n\t\tAND ")
+	}
+
+	return fmt.Sprintf(queryGetProducts, soldColumn, join, where, strings.Join(orderBy, ", ")), args
+}
+
+func (r *ProductRepo) GetProducts(ctx context.Context, payload *dto.ProductGetPayload, cursor *dto.ProductCursor) (*[]dto.ProductResponse, error) {
+	query, args := buildGetProductsQuery(payload, cursor)
 
-	rows, err := r.db.Query(ctx, queryGetProducts, args)
+	rows, err := r.db.Query(ctx, query, args)
 	if err != nil {
 		return nil, customErrors.HandlePgError(err, "failed get product")
 	}
@@ -191,11 +415,14 @@ func (r *ProductRepo) GetProducts(ctx context.Context, payload *dto.ProductGetPa
 			&product.Qty,
 			&product.Price,
 			&product.Sku,
+			&product.Description,
+			&product.Version,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 			&product.FileID,
 			&product.FileURI,
 			&product.FileThumbnailURI,
+			&product.SoldQty,
 		); err != nil {
 			return nil, err
 		}
@@ -203,29 +430,75 @@ func (r *ProductRepo) GetProducts(ctx context.Context, payload *dto.ProductGetPa
 		products = append(products, product)
 	}
 
+	related := make([]*dto.ProductResponse, len(products))
+	for i := range products {
+		related[i] = &products[i]
+	}
+	if err := r.attachRelations(ctx, related...); err != nil {
+		return nil, err
+	}
+
 	return &products, nil
 }
 
-func (r *ProductRepo) UpdateProduct(ctx context.Context, ID, sellerID *int, payload *dto.ProductPayload) (*dto.ProductResponse, error) {
+// toPrefixTsQuery turns free text into a tsquery matching every word as a
+// prefix, e.g. "kopi sus" becomes "kopi:* & sus:*". It returns nil when the
+// text holds no searchable word.
+func toPrefixTsQuery(q *string) *string {
+	if q == nil {
+		return nil
+	}
+
+	words := strings.FieldsFunc(*q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	for i, word := range words {
+		words[i] = strings.ToLower(word) + ":*"
+	}
+	tsQuery := strings.Join(words, " & ")
+	return &tsQuery
+}
+
+// UpdateProduct only changes the columns whose payload field is set. A
+// non-nil version makes the update conditional on the stored version.
+func (r *ProductRepo) UpdateProduct(ctx context.Context, ID, sellerID, version *int, payload *dto.ProductUpdatePayload) (*dto.ProductResponse, error) {
 	var product dto.ProductResponse
 	args := pgx.NamedArgs{
-		"ID":       &ID,
-		"sellerID": &sellerID,
-		"name":     &payload.Name,
-		"category": &payload.Category,
-		"qty":      &payload.Qty,
-		"price":    &payload.Price,
-		"sku":      &payload.Sku,
-		"fileID":   &payload.FileID,
+		"ID":          &ID,
+		"sellerID":    &sellerID,
+		"version":     version,
+		"name":        payload.Name,
+		"category":    payload.Category,
+		"qty":         payload.Qty,
+		"price":       payload.Price,
+		"sku":         payload.Sku,
+		"description": payload.Description,
+		"fileID":      payload.FileID,
 	}
 
-	err := r.db.QueryRow(ctx, queryUpdateProduct, args).Scan(
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "could not begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setPriceChangedBy(ctx, tx, *sellerID); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, queryUpdateProduct, args).Scan(
 		&product.ProductID,
 		&product.Name,
 		&product.Category,
 		&product.Qty,
 		&product.Price,
 		&product.Sku,
+		&product.Description,
+		&product.Version,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 		&product.FileID,
@@ -233,58 +506,235 @@ func (r *ProductRepo) UpdateProduct(ctx context.Context, ID, sellerID *int, payl
 		&product.FileThumbnailURI,
 	)
 	if err != nil {
-		return nil, customErrors.HandlePgError(err, "failed update product")
+		return nil, customErrors.HandlePgConstraintError(err, "failed update product", productConstraints)
+	}
+
+	if payload.FileIDs != nil {
+		if err := replaceProductFiles(ctx, tx, product.ProductID, payload.FileIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	if err := r.attachRelations(ctx, &product); err != nil {
+		return nil, err
 	}
 
 	return &product, nil
 }
 
-func (r *ProductRepo) DeleteProduct(ctx context.Context, ID, sellerID *int) error {
-	deleteFromPivotArgs := pgx.NamedArgs{"ID": &ID}
-	deleteFromProductsArgs := pgx.NamedArgs{"ID": &ID, "sellerID": &sellerID}
+// replaceProductFiles replaces the images of a product with fileIDs, in
+// order. The first file is the primary image. Callers write the products row
+// in the same transaction, which moves its version and so its ETag.
+func replaceProductFiles(ctx context.Context, tx pgx.Tx, productID string, fileIDs []string) error {
+	args := pgx.NamedArgs{"productID": productID, "fileIDs": fileIDs}
 
-	tx, err := r.db.Begin(ctx)
+	if _, err := tx.Exec(ctx, queryDeleteProductFiles, args); err != nil {
+		return customErrors.HandlePgError(err, "failed delete product images")
+	}
+	if _, err := tx.Exec(ctx, queryInsertProductFiles, args); err != nil {
+		return customErrors.HandlePgError(err, "failed insert product images")
+	}
+
+	return nil
+}
+
+// attachRelations loads the images and variant summaries of products.
+func (r *ProductRepo) attachRelations(ctx context.Context, products ...*dto.ProductResponse) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	if err := r.attachImages(ctx, products...); err != nil {
+		return err
+	}
+	return r.attachVariantSummaries(ctx, products...)
+}
+
+// attachImages loads the ordered images of products with a single query.
+func (r *ProductRepo) attachImages(ctx context.Context, products ...*dto.ProductResponse) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]string, 0, len(products))
+	productsByID := make(map[string]*dto.ProductResponse, len(products))
+	for _, product := range products {
+		product.Images = []dto.ProductImage{}
+		productIDs = append(productIDs, product.ProductID)
+		productsByID[product.ProductID] = product
+	}
+
+	rows, err := r.db.Query(ctx, queryGetProductImages, pgx.NamedArgs{"productIDs": productIDs})
 	if err != nil {
-		return customErrors.HandlePgError(err, "could not begin transaction")
+		return customErrors.HandlePgError(err, "failed get product images")
 	}
-	batch := &pgx.Batch{}
+	defer rows.Close()
 
-	batch.Queue(queryDeleteProductFromPivot, deleteFromPivotArgs)
-	batch.Queue(queryDeleteProductFromProducts, deleteFromProductsArgs)
+	for rows.Next() {
+		var productID string
+		var position int
+		var image dto.ProductImage
+		if err := rows.Scan(
+			&productID,
+			&image.FileID,
+			&image.FileURI,
+			&image.FileThumbnailURI,
+			&position,
+		); err != nil {
+			return err
+		}
 
-	batchResults := tx.SendBatch(ctx, batch)
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
+		image.IsPrimary = position == 0
+		if product, found := productsByID[productID]; found {
+			product.Images = append(product.Images, image)
 		}
-	}()
+	}
+
+	return rows.Err()
+}
 
-	// Delete product from pivot
-	_, err = batchResults.Exec()
+func (r *ProductRepo) GetProductFileIDs(ctx context.Context, ID, sellerID *int) ([]string, error) {
+	args := pgx.NamedArgs{"ID": &ID, "sellerID": &sellerID}
+
+	rows, err := r.db.Query(ctx, queryGetProductFileIDs, args)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get product images")
+	}
+
+	fileIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
 	if err != nil {
-		return customErrors.HandlePgError(err, "could not execute batch query")
+		return nil, customErrors.HandlePgError(err, "failed get product images")
 	}
 
-	// Delete product from products
-	result, err := batchResults.Exec()
+	return fileIDs, nil
+}
+
+// DeleteProduct soft deletes a product owned by sellerID, or any product
+// when sellerID is nil. A non-nil version makes the delete conditional on
+// the stored version. Purchase history of the product is kept.
+func (r *ProductRepo) DeleteProduct(ctx context.Context, ID, sellerID, version *int) error {
+	args := pgx.NamedArgs{"ID": &ID, "sellerID": &sellerID, "version": version}
+
+	result, err := r.db.Exec(ctx, queryDeleteProduct, args)
 	if err != nil {
-		return customErrors.HandlePgError(err, "could not execute batch query")
+		return customErrors.HandlePgError(err, "failed delete product")
 	}
 	if result.RowsAffected() != 1 {
-		err = customErrors.ErrNotFound
-		return customErrors.HandlePgError(err, "product not found")
+		return customErrors.HandlePgError(customErrors.ErrNotFound, "product not found")
 	}
 
-	if err := batchResults.Close(); err != nil {
-		return fmt.Errorf("could not close batch result: %w", err)
-	}
+	return nil
+}
 
-	if err = tx.Commit(ctx); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
+// ModerateProduct takes a product down on behalf of an admin. Unlike a
+// seller delete the product is marked as moderated, so it can't be restored.
+func (r *ProductRepo) ModerateProduct(ctx context.Context, ID *int) error {
+	args := pgx.NamedArgs{"ID": &ID}
+
+	result, err := r.db.Exec(ctx, queryModerateProduct, args)
+	if err != nil {
+		return customErrors.HandlePgError(err, "failed moderate product")
 	}
+	if result.RowsAffected() != 1 {
+		return customErrors.HandlePgError(customErrors.ErrNotFound, "product not found")
+	}
+
 	return nil
 }
 
+// RestoreProduct undeletes a product the seller deleted after deletedAfter.
+// Products taken down by an admin are never restored.
+func (r *ProductRepo) RestoreProduct(ctx context.Context, ID, sellerID *int, deletedAfter time.Time) (*dto.ProductResponse, error) {
+	var product dto.ProductResponse
+	args := pgx.NamedArgs{"ID": &ID, "sellerID": &sellerID, "deletedAfter": deletedAfter}
+
+	err := r.db.QueryRow(ctx, queryRestoreProduct, args).Scan(
+		&product.ProductID,
+		&product.Name,
+		&product.Category,
+		&product.Qty,
+		&product.Price,
+		&product.Sku,
+		&product.Description,
+		&product.Version,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.FileID,
+		&product.FileURI,
+		&product.FileThumbnailURI,
+	)
+	if err != nil {
+		return nil, customErrors.HandlePgConstraintError(err, "failed restore product", productConstraints)
+	}
+
+	if err := r.attachRelations(ctx, &product); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// PurgeProducts hard deletes products soft deleted before deletedBefore.
+// Products with purchase history are never purged.
+func (r *ProductRepo) PurgeProducts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := pgx.NamedArgs{"deletedBefore": deletedBefore}
+
+	result, err := r.db.Exec(ctx, queryPurgeProducts, args)
+	if err != nil {
+		return 0, customErrors.HandlePgError(err, "failed purge products")
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *ProductRepo) GetProductBySku(ctx context.Context, sellerID *int, sku string) (*dto.ProductResponse, error) {
+	var product dto.ProductResponse
+	args := pgx.NamedArgs{"sellerID": &sellerID, "sku": sku}
+
+	err := r.db.QueryRow(ctx, queryGetProductBySku, args).Scan(
+		&product.ProductID,
+		&product.Name,
+		&product.Category,
+		&product.Qty,
+		&product.Price,
+		&product.Sku,
+		&product.Description,
+		&product.Version,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.FileID,
+		&product.FileURI,
+		&product.FileThumbnailURI,
+	)
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get product")
+	}
+
+	if err := r.attachRelations(ctx, &product); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// GetProductVersion returns the stored version of a product owned by
+// sellerID, or of any product when sellerID is nil.
+func (r *ProductRepo) GetProductVersion(ctx context.Context, ID, sellerID *int) (int, error) {
+	var version int
+	args := pgx.NamedArgs{"ID": &ID, "sellerID": &sellerID}
+
+	err := r.db.QueryRow(ctx, queryGetProductVersion, args).Scan(&version)
+	if err != nil {
+		return 0, customErrors.HandlePgError(err, "failed get product version")
+	}
+
+	return version, nil
+}
+
 func (r *ProductRepo) GetProductsByIDs(ctx context.Context, ids []int) ([]dto.ProductWithSeller, error) {
 	if len(ids) == 0 {
 		return []dto.ProductWithSeller{}, nil
@@ -316,15 +766,19 @@ func (r *ProductRepo) GetProductsByIDs(ctx context.Context, ids []int) ([]dto.Pr
 			&product.Qty,
 			&product.Price,
 			&product.Sku,
+			&product.Description,
+			&product.Version,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 			&product.FileID,
 			&product.FileURI,
 			&product.FileThumbnailURI,
 			&product.SellerId,
+			&product.BankAccountID,
 			&product.BankAccountName,
 			&product.BankAccountHolder,
 			&product.BankAccountNumber,
+			&product.CategoryPath,
 		); err != nil {
 			return nil, err
 		}
@@ -332,5 +786,13 @@ func (r *ProductRepo) GetProductsByIDs(ctx context.Context, ids []int) ([]dto.Pr
 		products = append(products, product)
 	}
 
+	related := make([]*dto.ProductResponse, len(products))
+	for i := range products {
+		related[i] = &products[i].ProductResponse
+	}
+	if err := r.attachRelations(ctx, related...); err != nil {
+		return nil, err
+	}
+
 	return products, nil
 }
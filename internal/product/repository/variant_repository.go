@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	queryListVariants = `
+	SELECT v.id, v.product_id, v.sku, v.size, v.color, v.qty, v.price, v.created_at, v.updated_at
+	FROM product_variants v
+	JOIN products p ON p.id = v.product_id
+	WHERE v.product_id = @productID AND v.deleted_at IS NULL AND p.deleted_at IS NULL
+	ORDER BY v.price, v.id;`
+	queryCreateVariant = `
+	INSERT INTO product_variants (product_id, sku, size, color, qty, price)
+	SELECT p.id, @sku, @size, @color, @qty, @price
+	FROM products p
+	WHERE p.id = @productID AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	RETURNING id, product_id, sku, size, color, qty, price, created_at, updated_at;`
+	queryUpdateVariant = `
+	UPDATE product_variants v
+	SET
+		sku = COALESCE(@sku, v.sku),
+		size = COALESCE(@size, v.size),
+		color = COALESCE(@color, v.color),
+		qty = COALESCE(@qty, v.qty),
+		price = COALESCE(@price, v.price)
+	FROM products p
+	WHERE v.id = @ID AND v.product_id = @productID AND v.deleted_at IS NULL
+		AND p.id = v.product_id AND p.seller_id = @sellerID AND p.deleted_at IS NULL
+	RETURNING v.id, v.product_id, v.sku, v.size, v.color, v.qty, v.price, v.created_at, v.updated_at;`
+	queryDeleteVariant = `
+	UPDATE product_variants v
+	SET deleted_at = NOW()
+	FROM products p
+	WHERE v.id = @ID AND v.product_id = @productID AND v.deleted_at IS NULL
+		AND p.id = v.product_id AND p.seller_id = @sellerID AND p.deleted_at IS NULL;`
+	queryGetVariantsByIDs = `
+	SELECT id, product_id, sku, size, color, qty, price, created_at, updated_at
+	FROM product_variants
+	WHERE id = ANY(@IDs::BIGINT[]) AND deleted_at IS NULL;`
+	queryCountVariants = `
+	SELECT COUNT(*) FROM product_variants
+	WHERE product_id = @productID AND deleted_at IS NULL;`
+	queryTouchProduct        = "UPDATE products SET version = version + 1 WHERE id = @productID;"
+	queryGetVariantSummaries = `
+	SELECT product_id::TEXT, MIN(price), MAX(price), SUM(qty), COUNT(*)
+	FROM product_variants
+	WHERE product_id = ANY(@productIDs::TEXT[]::BIGINT[]) AND deleted_at IS NULL
+	GROUP BY product_id;`
+)
+
+func (r *ProductRepo) ListVariants(ctx context.Context, productID int) ([]model.ProductVariant, error) {
+	rows, err := r.db.Query(ctx, queryListVariants, pgx.NamedArgs{"productID": productID})
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get variants")
+	}
+	defer rows.Close()
+
+	var variants []model.ProductVariant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+// CreateVariant adds a variant to a product owned by sellerID.
+func (r *ProductRepo) CreateVariant(ctx context.Context, productID, sellerID int, payload *dto.ProductVariantPayload) (model.ProductVariant, error) {
+	args := pgx.NamedArgs{
+		"productID": productID,
+		"sellerID":  sellerID,
+		"sku":       payload.Sku,
+		"size":      payload.Size,
+		"color":     payload.Color,
+		"qty":       payload.Qty,
+		"price":     payload.Price,
+	}
+
+	var variant model.ProductVariant
+	err := r.withPriceChangedBy(ctx, sellerID, func(tx pgx.Tx) error {
+		var err error
+		variant, err = scanVariant(tx.QueryRow(ctx, queryCreateVariant, args))
+		if err != nil {
+			return err
+		}
+		return touchProduct(ctx, tx, productID)
+	})
+	if err != nil {
+		return model.ProductVariant{}, customErrors.HandlePgConstraintError(err, "failed create variant", productConstraints)
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant only changes the columns whose payload field is set.
+func (r *ProductRepo) UpdateVariant(ctx context.Context, ID, productID, sellerID int, payload *dto.ProductVariantUpdatePayload) (model.ProductVariant, error) {
+	args := pgx.NamedArgs{
+		"ID":        ID,
+		"productID": productID,
+		"sellerID":  sellerID,
+		"sku":       payload.Sku,
+		"size":      payload.Size,
+		"color":     payload.Color,
+		"qty":       payload.Qty,
+		"price":     payload.Price,
+	}
+
+	var variant model.ProductVariant
+	err := r.withPriceChangedBy(ctx, sellerID, func(tx pgx.Tx) error {
+		var err error
+		variant, err = scanVariant(tx.QueryRow(ctx, queryUpdateVariant, args))
+		if err != nil {
+			return err
+		}
+		return touchProduct(ctx, tx, productID)
+	})
+	if err != nil {
+		return model.ProductVariant{}, customErrors.HandlePgConstraintError(err, "failed update variant", productConstraints)
+	}
+
+	return variant, nil
+}
+
+// DeleteVariant soft deletes a variant so purchases keep referencing it.
+func (r *ProductRepo) DeleteVariant(ctx context.Context, ID, productID, sellerID int) error {
+	args := pgx.NamedArgs{"ID": ID, "productID": productID, "sellerID": sellerID}
+
+	return r.withPriceChangedBy(ctx, sellerID, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, queryDeleteVariant, args)
+		if err != nil {
+			return customErrors.HandlePgError(err, "failed delete variant")
+		}
+		if result.RowsAffected() != 1 {
+			return customErrors.HandlePgError(customErrors.ErrNotFound, "variant not found")
+		}
+		return touchProduct(ctx, tx, productID)
+	})
+}
+
+// touchProduct moves the version of a product whose variants changed, so the
+// ETag of the product changes with them. The rollup trigger only writes the
+// product when its qty or price moves.
+func touchProduct(ctx context.Context, tx pgx.Tx, productID int) error {
+	if _, err := tx.Exec(ctx, queryTouchProduct, pgx.NamedArgs{"productID": productID}); err != nil {
+		return customErrors.HandlePgError(err, "failed update product version")
+	}
+	return nil
+}
+
+func (r *ProductRepo) GetVariantsByIDs(ctx context.Context, ids []int) ([]model.ProductVariant, error) {
+	if len(ids) == 0 {
+		return []model.ProductVariant{}, nil
+	}
+
+	rows, err := r.db.Query(ctx, queryGetVariantsByIDs, pgx.NamedArgs{"IDs": ids})
+	if err != nil {
+		return nil, customErrors.HandlePgError(err, "failed get variants")
+	}
+	defer rows.Close()
+
+	var variants []model.ProductVariant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+func (r *ProductRepo) CountVariants(ctx context.Context, productID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, queryCountVariants, pgx.NamedArgs{"productID": productID}).Scan(&count)
+	if err != nil {
+		return 0, customErrors.HandlePgError(err, "failed count variants")
+	}
+
+	return count, nil
+}
+
+// attachVariantSummaries sets the price range and availability of products,
+// taken from their variants when they have any.
+func (r *ProductRepo) attachVariantSummaries(ctx context.Context, products ...*dto.ProductResponse) error {
+	productIDs := make([]string, 0, len(products))
+	productsByID := make(map[string]*dto.ProductResponse, len(products))
+	for _, product := range products {
+		product.PriceRange = dto.PriceRange{Min: product.Price, Max: product.Price}
+		product.AvailableQty = product.Qty
+		product.VariantCount = 0
+		productIDs = append(productIDs, product.ProductID)
+		productsByID[product.ProductID] = product
+	}
+
+	rows, err := r.db.Query(ctx, queryGetVariantSummaries, pgx.NamedArgs{"productIDs": productIDs})
+	if err != nil {
+		return customErrors.HandlePgError(err, "failed get variant summaries")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var summary dto.PriceRange
+		var availableQty, variantCount int
+		if err := rows.Scan(&productID, &summary.Min, &summary.Max, &availableQty, &variantCount); err != nil {
+			return err
+		}
+
+		if product, found := productsByID[productID]; found {
+			product.PriceRange = summary
+			product.AvailableQty = availableQty
+			product.VariantCount = variantCount
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanVariant(row pgx.Row) (model.ProductVariant, error) {
+	var variant model.ProductVariant
+	err := row.Scan(
+		&variant.ID,
+		&variant.ProductID,
+		&variant.Sku,
+		&variant.Size,
+		&variant.Color,
+		&variant.Qty,
+		&variant.Price,
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+	)
+	return variant, err
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"tutup-lapak/internal/product/dto"
+
+	"github.com/pkg/errors"
+)
+
+// importChunkSize is how many rows of an import share a transaction.
+const importChunkSize = 200
+
+// ImportProducts creates the products of rows, or updates the seller's
+// products with the same sku when upsert is set. Rows are checked like
+// CreateProduct and written in chunks, a failing row is reported without
+// failing the others. Each chunk commits on its own, so an import aborted
+// midway keeps the chunks written before.
+func (u *ProductUsecase) ImportProducts(ctx context.Context, sellerID int, rows []dto.ProductImportRow, upsert bool) (*dto.ProductImportResponse, error) {
+	response := &dto.ProductImportResponse{Errors: []dto.ProductImportRowError{}}
+	fail := func(row dto.ProductImportRow, msg string) {
+		response.Failed++
+		response.Errors = append(response.Errors, dto.ProductImportRowError{
+			Row:   row.Row,
+			Sku:   row.Payload.Sku,
+			Error: msg,
+		})
+	}
+
+	categories, err := u.categoryRepo.ListCategories(ctx, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get categories")
+	}
+	categoryNames := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Name] = true
+	}
+
+	ownedFiles, err := u.ownedFiles(ctx, sellerID, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make([]dto.ProductImportRow, 0, len(rows))
+	for _, row := range rows {
+		if !categoryNames[row.Payload.Category] {
+			fail(row, "category not exists")
+			continue
+		}
+
+		images := row.Payload.FileIDs
+		if row.Payload.FileID != "" {
+			images = withPrimaryImage(images, row.Payload.FileID)
+		}
+		if len(images) > maxProductImages {
+			fail(row, "too many images")
+			continue
+		}
+		owned := true
+		for _, fileID := range images {
+			owned = owned && ownedFiles[fileID]
+		}
+		if !owned {
+			fail(row, "fileId not exists")
+			continue
+		}
+		row.Payload.FileIDs = images
+		row.Payload.FileID = images[0]
+
+		valid = append(valid, row)
+	}
+
+	for start := 0; start < len(valid); start += importChunkSize {
+		chunk := valid[start:min(start+importChunkSize, len(valid))]
+		payloads := make([]dto.ProductPayload, len(chunk))
+		for i, row := range chunk {
+			payloads[i] = row.Payload
+		}
+
+		created, rowErrs, err := u.repo.ImportProducts(ctx, sellerID, payloads, upsert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to import rows from %d", chunk[0].Row)
+		}
+
+		for i, row := range chunk {
+			switch {
+			case rowErrs[i] != nil:
+				fail(row, rowErrs[i].Error())
+			case created[i]:
+				response.Created++
+			default:
+				response.Updated++
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// ownedFiles looks up every file referenced by rows at once and returns the
+// ones uploaded by sellerID.
+func (u *ProductUsecase) ownedFiles(ctx context.Context, sellerID int, rows []dto.ProductImportRow) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	var ids []int
+	for _, row := range rows {
+		for _, fileID := range append([]string{row.Payload.FileID}, row.Payload.FileIDs...) {
+			id, err := strconv.Atoi(fileID)
+			if err != nil || seen[fileID] {
+				continue
+			}
+			seen[fileID] = true
+			ids = append(ids, id)
+		}
+	}
+
+	owned := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return owned, nil
+	}
+
+	files, err := u.fileRepo.ListFiles(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get files")
+	}
+	for _, file := range files {
+		if file.SellerID != nil && *file.SellerID == sellerID {
+			owned[strconv.Itoa(file.ID)] = true
+		}
+	}
+
+	return owned, nil
+}
+
+// ExportProducts passes every product of sellerID to fn in the shape
+// accepted by ImportProducts.
+func (u *ProductUsecase) ExportProducts(ctx context.Context, sellerID int, fn func(dto.ProductPayload) error) error {
+	return u.repo.ExportProducts(ctx, sellerID, fn)
+}
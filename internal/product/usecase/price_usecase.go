@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model/converter"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+// GetPriceHistory lists the price changes of a product, newest first.
+func (u *ProductUsecase) GetPriceHistory(ctx context.Context, productID int, query *dto.PriceHistoryQuery) ([]dto.PriceChangeResponse, error) {
+	if _, err := u.repo.GetProductVersion(ctx, &productID, nil); err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+		}
+		return nil, err
+	}
+
+	changes, err := u.repo.GetPriceHistory(ctx, productID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ToPriceChangeResponses(changes), nil
+}
+
+// CreatePriceSchedule refuses products with variants, whose price follows
+// their variants, and windows overlapping another open schedule.
+func (u *ProductUsecase) CreatePriceSchedule(ctx context.Context, productID, sellerID int, payload *dto.PriceSchedulePayload) (*dto.PriceScheduleResponse, error) {
+	if payload.EndsAt != nil {
+		if !payload.EndsAt.After(payload.StartsAt) {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "endsAt must be after startsAt")
+		}
+		if !payload.EndsAt.After(time.Now()) {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "endsAt must be in the future")
+		}
+	}
+
+	variantCount, err := u.repo.CountVariants(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if variantCount > 0 {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "price of a product with variants is set on its variants")
+	}
+
+	schedule, err := u.repo.CreatePriceSchedule(ctx, productID, sellerID, payload)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToPriceScheduleResponse(schedule)
+	return &response, nil
+}
+
+func (u *ProductUsecase) ListPriceSchedules(ctx context.Context, productID, sellerID int) ([]dto.PriceScheduleResponse, error) {
+	if _, err := u.repo.GetProductVersion(ctx, &productID, &sellerID); err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+		}
+		return nil, err
+	}
+
+	schedules, err := u.repo.ListPriceSchedules(ctx, productID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ToPriceScheduleResponses(schedules), nil
+}
+
+// CancelPriceSchedule drops a pending schedule. An active schedule ends
+// right away and its price is reverted by the next worker run.
+func (u *ProductUsecase) CancelPriceSchedule(ctx context.Context, ID, productID, sellerID int) (*dto.PriceScheduleResponse, error) {
+	schedule, err := u.repo.CancelPriceSchedule(ctx, ID, productID, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "price schedule not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToPriceScheduleResponse(schedule)
+	return &response, nil
+}
+
+// ApplyPriceSchedules starts and ends every due schedule and returns how
+// many it handled.
+func (u *ProductUsecase) ApplyPriceSchedules(ctx context.Context) (int, error) {
+	applied := 0
+	for {
+		found, err := u.repo.ApplyNextPriceSchedule(ctx)
+		if err != nil {
+			return applied, err
+		}
+		if !found {
+			return applied, nil
+		}
+		applied++
+	}
+}
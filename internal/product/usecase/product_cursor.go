@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+func encodeProductCursor(cursor dto.ProductCursor) *string {
+	raw, _ := json.Marshal(cursor)
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return &token
+}
+
+// decodeProductCursor rejects tokens that are malformed or were issued for
+// another sort order.
+func decodeProductCursor(token, sortBy string) (*dto.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "invalid cursor")
+	}
+
+	var cursor dto.ProductCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID < 1 {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "invalid cursor")
+	}
+
+	if cursor.SortBy != sortBy {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "cursor does not match sortBy")
+	}
+
+	if !validCursorValue(cursor.Value, sortBy) {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// validCursorValue checks the value parses as the sort key of sortBy, the
+// query casts it to that type.
+func validCursorValue(value, sortBy string) bool {
+	var err error
+	switch sortBy {
+	case "newest":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "cheapest":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "":
+		return value == ""
+	default:
+		// sold-N
+		_, err = strconv.ParseInt(value, 10, 64)
+	}
+	return err == nil
+}
+
+// productCursorAt builds the cursor pointing at product for the given sort.
+func productCursorAt(product dto.ProductResponse, sortBy string, backward bool) *string {
+	id, _ := strconv.Atoi(product.ProductID)
+	cursor := dto.ProductCursor{SortBy: sortBy, ID: id, Backward: backward}
+
+	switch sortBy {
+	case "newest":
+		newest := product.CreatedAt
+		if product.UpdatedAt.After(newest) {
+			newest = product.UpdatedAt
+		}
+		cursor.Value = newest.Format(time.RFC3339Nano)
+	case "cheapest":
+		cursor.Value = strconv.Itoa(product.Price)
+	case "":
+	default:
+		// sold-N
+		cursor.Value = strconv.FormatInt(product.SoldQty, 10)
+	}
+
+	return encodeProductCursor(cursor)
+}
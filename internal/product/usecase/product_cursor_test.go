@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"tutup-lapak/internal/product/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	product := dto.ProductResponse{
+		ProductID: "42",
+		Price:     15000,
+		SoldQty:   7,
+		CreatedAt: created,
+		UpdatedAt: created.Add(90 * time.Minute),
+	}
+
+	tests := []struct {
+		name      string
+		sortBy    string
+		backward  bool
+		wantValue string
+	}{
+		{name: "default order", sortBy: "", wantValue: ""},
+		{name: "newest uses the later timestamp", sortBy: "newest", wantValue: "2024-05-01T11:30:00Z"},
+		{name: "cheapest", sortBy: "cheapest", wantValue: "15000"},
+		{name: "sold", sortBy: "sold-7", wantValue: "7"},
+		{name: "backward", sortBy: "cheapest", backward: true, wantValue: "15000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := productCursorAt(product, tt.sortBy, tt.backward)
+
+			cursor, err := decodeProductCursor(*token, tt.sortBy)
+			if err != nil {
+				t.Fatalf("decodeProductCursor() error = %v", err)
+			}
+
+			want := dto.ProductCursor{SortBy: tt.sortBy, Value: tt.wantValue, ID: 42, Backward: tt.backward}
+			if *cursor != want {
+				t.Errorf("decodeProductCursor() = %+v, want %+v", *cursor, want)
+			}
+		})
+	}
+}
+
+func TestDecodeProductCursorRejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		sortBy string
+	}{
+		{name: "not base64", token: "***", sortBy: ""},
+		{name: "not json", token: encode("cursor"), sortBy: ""},
+		{name: "missing id", token: encode(`{"s":""}`), sortBy: ""},
+		{name: "negative id", token: encode(`{"s":"","id":-1}`), sortBy: ""},
+		{name: "other sort order", token: encode(`{"s":"newest","v":"2024-05-01T10:00:00Z","id":1}`), sortBy: "cheapest"},
+		{name: "bad timestamp", token: encode(`{"s":"newest","v":"yesterday","id":1}`), sortBy: "newest"},
+		{name: "price out of range", token: encode(`{"s":"cheapest","v":"99999999999","id":1}`), sortBy: "cheapest"},
+		{name: "bad sold count", token: encode(`{"s":"sold-7","v":"1; DROP","id":1}`), sortBy: "sold-7"},
+		{name: "value on default order", token: encode(`{"s":"","v":"1","id":1}`), sortBy: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeProductCursor(tt.token, tt.sortBy)
+			if !errors.Is(err, customErrors.ErrBadRequest) {
+				t.Errorf("decodeProductCursor() error = %v, want ErrBadRequest", err)
+			}
+		})
+	}
+}
@@ -2,21 +2,58 @@ package usecase
 
 import (
 	"context"
+	"slices"
+	"strconv"
+	"time"
+	categoryModel "tutup-lapak/internal/category/model"
+	categoryRepository "tutup-lapak/internal/category/repository"
+	fileRepository "tutup-lapak/internal/file/repository"
 	"tutup-lapak/internal/product/dto"
 	"tutup-lapak/internal/product/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// productRetention is how long soft deleted products can be restored.
+	productRetention = 30 * 24 * time.Hour
+	maxProductImages = 10
 )
 
 type ProductUsecase struct {
-	repo *repository.ProductRepo
+	repo         *repository.ProductRepo
+	fileRepo     *fileRepository.FileRepository
+	categoryRepo *categoryRepository.CategoryRepository
 }
 
-func NewProductUsecase(repo *repository.ProductRepo) *ProductUsecase {
+func NewProductUsecase(repo *repository.ProductRepo, fileRepo *fileRepository.FileRepository, categoryRepo *categoryRepository.CategoryRepository) *ProductUsecase {
 	return &ProductUsecase{
-		repo: repo,
+		repo:         repo,
+		fileRepo:     fileRepo,
+		categoryRepo: categoryRepo,
 	}
 }
 
+// CreateProduct stores fileId as the primary image followed by the other
+// fileIds in order.
 func (u *ProductUsecase) CreateProduct(ctx context.Context, sellerID *int, payload *dto.ProductPayload) (*dto.ProductResponse, error) {
+	category, err := u.resolveCategory(ctx, payload.Category)
+	if err != nil {
+		return nil, err
+	}
+	payload.Category = category
+
+	images := payload.FileIDs
+	if payload.FileID != "" {
+		images = withPrimaryImage(images, payload.FileID)
+	}
+	if err := u.verifyImages(ctx, *sellerID, images); err != nil {
+		return nil, err
+	}
+	payload.FileIDs = images
+	payload.FileID = images[0]
+
 	product, err := u.repo.CreateProduct(ctx, sellerID, payload)
 	if err != nil {
 		return nil, err
@@ -25,23 +62,284 @@ func (u *ProductUsecase) CreateProduct(ctx context.Context, sellerID *int, paylo
 }
 
 func (u *ProductUsecase) GetProducts(ctx context.Context, payload *dto.ProductGetPayload) (*[]dto.ProductResponse, error) {
-	products, err := u.repo.GetProducts(ctx, payload)
+	if err := u.verifyCategoryFilter(ctx, payload.Category); err != nil {
+		return nil, err
+	}
+
+	products, err := u.repo.GetProducts(ctx, payload, nil)
 	if err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
-func (u *ProductUsecase) UpdateProduct(ctx context.Context, ID, sellerID *int, payload *dto.ProductPayload) (*dto.ProductResponse, error) {
-	product, err := u.repo.UpdateProduct(ctx, ID, sellerID, payload)
+// GetProductPage pages through products with keyset cursors instead of an
+// offset. It fetches one extra row to tell whether another page exists.
+func (u *ProductUsecase) GetProductPage(ctx context.Context, payload *dto.ProductGetPayload) (*dto.ProductPageResponse, error) {
+	if err := u.verifyCategoryFilter(ctx, payload.Category); err != nil {
+		return nil, err
+	}
+
+	sortBy := ""
+	if payload.SortBy != nil {
+		sortBy = *payload.SortBy
+	}
+
+	var cursor *dto.ProductCursor
+	if payload.Cursor != nil {
+		if payload.Q != nil {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "cursor cannot be combined with q")
+		}
+
+		var err error
+		cursor, err = decodeProductCursor(*payload.Cursor, sortBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := *payload
+	query.Limit = payload.Limit + 1
+	query.Offset = 0
+	if cursor == nil {
+		query.Offset = payload.Offset
+	}
+
+	products, err := u.repo.GetProducts(ctx, &query, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	data := *products
+	hasMore := len(data) > payload.Limit
+	if hasMore {
+		data = data[:payload.Limit]
+	}
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		slices.Reverse(data)
+	}
+
+	page := dto.ProductPageResponse{Data: data}
+	if len(data) == 0 {
+		return &page, nil
+	}
+
+	// search results are ranked by relevance and can only be paged by offset
+	if payload.Q != nil {
+		return &page, nil
+	}
+
+	if hasMore || backward {
+		page.NextCursor = productCursorAt(data[len(data)-1], sortBy, false)
+	}
+	if (backward && hasMore) || (!backward && (cursor != nil || payload.Offset > 0)) {
+		page.PrevCursor = productCursorAt(data[0], sortBy, true)
+	}
+
+	return &page, nil
+}
+
+func (u *ProductUsecase) GetProductBySku(ctx context.Context, sellerID *int, sku string) (*dto.ProductResponse, error) {
+	product, err := u.repo.GetProductBySku(ctx, sellerID, sku)
 	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+		}
 		return nil, err
 	}
 	return product, nil
 }
 
-func (u *ProductUsecase) DeleteProduct(ctx context.Context, ID, sellerID *int) error {
-	err := u.repo.DeleteProduct(ctx, ID, sellerID)
+// UpdateProduct applies payload when the stored version equals version, or
+// unconditionally when version is nil.
+// fileIds replaces the images of the product, while fileId alone promotes a
+// file to primary image and keeps the others. The qty and price of a product
+// with variants are derived from the variants and cannot be patched.
+func (u *ProductUsecase) UpdateProduct(ctx context.Context, ID, sellerID, version *int, payload *dto.ProductUpdatePayload) (*dto.ProductResponse, error) {
+	if payload.Qty != nil || payload.Price != nil {
+		variantCount, err := u.repo.CountVariants(ctx, *ID)
+		if err != nil {
+			return nil, err
+		}
+		if variantCount > 0 {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "qty and price of a product with variants are set on its variants")
+		}
+	}
+
+	if payload.Category != nil {
+		category, err := u.resolveCategory(ctx, *payload.Category)
+		if err != nil {
+			return nil, err
+		}
+		payload.Category = &category
+	}
+
+	if payload.FileID != nil || payload.FileIDs != nil {
+		images := payload.FileIDs
+		if images == nil {
+			current, err := u.repo.GetProductFileIDs(ctx, ID, sellerID)
+			if err != nil {
+				return nil, err
+			}
+			if len(current) == 0 {
+				return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+			}
+			images = current
+		}
+		if payload.FileID != nil {
+			images = withPrimaryImage(images, *payload.FileID)
+		}
+
+		if err := u.verifyImages(ctx, *sellerID, images); err != nil {
+			return nil, err
+		}
+		payload.FileIDs = images
+		payload.FileID = &images[0]
+	}
+
+	product, err := u.repo.UpdateProduct(ctx, ID, sellerID, version, payload)
+	if err != nil {
+		return nil, u.checkVersion(ctx, ID, sellerID, version, err)
+	}
+	return product, nil
+}
+
+// DeleteProduct deletes the product when the stored version equals version,
+// or unconditionally when version is nil.
+func (u *ProductUsecase) DeleteProduct(ctx context.Context, ID, sellerID, version *int) error {
+	err := u.repo.DeleteProduct(ctx, ID, sellerID, version)
+	if err != nil {
+		return u.checkVersion(ctx, ID, sellerID, version, err)
+	}
+	return nil
+}
+
+// withPrimaryImage moves primary to the front of fileIDs.
+func withPrimaryImage(fileIDs []string, primary string) []string {
+	images := []string{primary}
+	for _, fileID := range fileIDs {
+		if fileID != primary {
+			images = append(images, fileID)
+		}
+	}
+	return images
+}
+
+// verifyImages checks that every file exists and was uploaded by sellerID.
+func (u *ProductUsecase) verifyImages(ctx context.Context, sellerID int, fileIDs []string) error {
+	if len(fileIDs) == 0 {
+		return errors.Wrap(customErrors.ErrBadRequest, "a product needs at least one image")
+	}
+	if len(fileIDs) > maxProductImages {
+		return errors.Wrapf(customErrors.ErrBadRequest, "a product can have at most %d images", maxProductImages)
+	}
+
+	ids := make([]int, len(fileIDs))
+	for i, fileID := range fileIDs {
+		id, err := strconv.Atoi(fileID)
+		if err != nil {
+			return errors.Wrap(customErrors.ErrBadRequest, "invalid file ID")
+		}
+		ids[i] = id
+	}
+
+	files, err := u.fileRepo.ListFiles(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to get files")
+	}
+
+	owned := 0
+	for _, file := range files {
+		if file.SellerID != nil && *file.SellerID == sellerID {
+			owned++
+		}
+	}
+	if owned != len(ids) {
+		return errors.Wrap(customErrors.ErrBadRequest, "fileId not exists")
+	}
+
+	return nil
+}
+
+// resolveCategory returns the name of the active category named category.
+func (u *ProductUsecase) resolveCategory(ctx context.Context, category string) (string, error) {
+	categories, err := u.categoryRepo.GetCategoriesByNames(ctx, []string{category})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get category")
+	}
+
+	for _, c := range categories {
+		if c.IsActive {
+			return c.Name, nil
+		}
+	}
+	return "", errors.Wrap(customErrors.ErrBadRequest, "category not exists")
+}
+
+// verifyCategoryFilter rejects filters naming unknown categories. Inactive
+// categories can still be filtered on.
+func (u *ProductUsecase) verifyCategoryFilter(ctx context.Context, filter []string) error {
+	if len(filter) == 0 {
+		return nil
+	}
+
+	categories, err := u.categoryRepo.GetCategoriesByNames(ctx, filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to get categories")
+	}
+
+	for _, value := range filter {
+		found := slices.ContainsFunc(categories, func(c categoryModel.Category) bool {
+			return c.Name == value
+		})
+		if !found {
+			return errors.Wrapf(customErrors.ErrBadRequest, "category %s not exists", value)
+		}
+	}
+	return nil
+}
+
+// checkVersion tells a missing product apart from a stale version once a
+// conditional write matched no rows.
+func (u *ProductUsecase) checkVersion(ctx context.Context, ID, sellerID, version *int, err error) error {
+	if version == nil || !errors.Is(err, customErrors.ErrNotFound) {
+		return err
+	}
+
+	current, versionErr := u.repo.GetProductVersion(ctx, ID, sellerID)
+	if versionErr != nil {
+		return versionErr
+	}
+
+	return errors.Wrapf(customErrors.ErrPreconditionFailed, "product version is %d", current)
+}
+
+// RestoreProduct undeletes a product within the retention period, products
+// taken down by an admin report as not found.
+func (u *ProductUsecase) RestoreProduct(ctx context.Context, ID, sellerID *int) (*dto.ProductResponse, error) {
+	product, err := u.repo.RestoreProduct(ctx, ID, sellerID, time.Now().Add(-productRetention))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "deleted product not found")
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// PurgeProducts hard deletes products that stayed soft deleted for longer
+// than the retention period.
+func (u *ProductUsecase) PurgeProducts(ctx context.Context) (*dto.ProductPurgeResponse, error) {
+	purged, err := u.repo.PurgeProducts(ctx, time.Now().Add(-productRetention))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ProductPurgeResponse{Purged: purged}, nil
+}
+
+func (u *ProductUsecase) ModerateDeleteProduct(ctx context.Context, ID *int) error {
+	err := u.repo.ModerateProduct(ctx, ID)
 	if err != nil {
 		return err
 	}
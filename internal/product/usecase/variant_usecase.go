@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"tutup-lapak/internal/product/dto"
+	"tutup-lapak/internal/product/model/converter"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+func (u *ProductUsecase) ListVariants(ctx context.Context, productID int) ([]dto.ProductVariantResponse, error) {
+	variants, err := u.repo.ListVariants(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ToProductVariantResponses(variants), nil
+}
+
+func (u *ProductUsecase) CreateVariant(ctx context.Context, productID, sellerID int, payload *dto.ProductVariantPayload) (*dto.ProductVariantResponse, error) {
+	variant, err := u.repo.CreateVariant(ctx, productID, sellerID, payload)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "product not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToProductVariantResponse(variant)
+	return &response, nil
+}
+
+func (u *ProductUsecase) UpdateVariant(ctx context.Context, ID, productID, sellerID int, payload *dto.ProductVariantUpdatePayload) (*dto.ProductVariantResponse, error) {
+	variant, err := u.repo.UpdateVariant(ctx, ID, productID, sellerID, payload)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "variant not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToProductVariantResponse(variant)
+	return &response, nil
+}
+
+func (u *ProductUsecase) DeleteVariant(ctx context.Context, ID, productID, sellerID int) error {
+	err := u.repo.DeleteVariant(ctx, ID, productID, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return errors.Wrap(customErrors.ErrNotFound, "variant not found")
+		}
+		return err
+	}
+	return nil
+}
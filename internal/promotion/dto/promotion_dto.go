@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// PromotionPayload creates a promotion covering productId, category and the
+// categories below it or, with neither, every product of the seller. A code
+// turns it into a voucher.
+type PromotionPayload struct {
+	Name          string     `json:"name" validate:"required,min=1,max=64"`
+	Code          *string    `json:"code" validate:"omitnil,min=3,max=32,alphanum"`
+	DiscountType  string     `json:"discountType" validate:"required,oneof=percentage fixed"`
+	DiscountValue int        `json:"discountValue" validate:"required,min=1"`
+	MaxDiscount   *int       `json:"maxDiscount" validate:"omitnil,min=1"`
+	ProductID     *string    `json:"productId" validate:"omitnil,number,excluded_with=Category"`
+	Category      *string    `json:"category" validate:"omitnil,min=1,max=64"`
+	MinSpend      int        `json:"minSpend" validate:"min=0"`
+	UsageLimit    *int       `json:"usageLimit" validate:"omitnil,min=1"`
+	StartsAt      *time.Time `json:"startsAt"`
+	EndsAt        *time.Time `json:"endsAt"`
+	IsActive      *bool      `json:"isActive"`
+}
+
+type PromotionUpdatePayload struct {
+	Name        *string    `json:"name" validate:"omitnil,min=1,max=64"`
+	MaxDiscount *int       `json:"maxDiscount" validate:"omitnil,min=1"`
+	MinSpend    *int       `json:"minSpend" validate:"omitnil,min=0"`
+	UsageLimit  *int       `json:"usageLimit" validate:"omitnil,min=1"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	IsActive    *bool      `json:"isActive"`
+}
+
+type PromotionResponse struct {
+	PromotionID   string     `json:"promotionId"`
+	Name          string     `json:"name"`
+	Code          *string    `json:"code"`
+	DiscountType  string     `json:"discountType"`
+	DiscountValue int        `json:"discountValue"`
+	MaxDiscount   *int       `json:"maxDiscount"`
+	ProductID     *string    `json:"productId"`
+	Category      *string    `json:"category"`
+	MinSpend      int        `json:"minSpend"`
+	UsageLimit    *int       `json:"usageLimit"`
+	UsageCount    int        `json:"usageCount"`
+	StartsAt      *time.Time `json:"startsAt"`
+	EndsAt        *time.Time `json:"endsAt"`
+	IsActive      bool       `json:"isActive"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
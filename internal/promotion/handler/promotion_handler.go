@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	custom_middleware "tutup-lapak/internal/middleware"
+	"tutup-lapak/internal/promotion/dto"
+	"tutup-lapak/internal/promotion/usecase"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type PromotionHandler struct {
+	UseCase  *usecase.PromotionUsecase
+	Validate *validator.Validate
+}
+
+func NewPromotionHandler(useCase *usecase.PromotionUsecase, validate *validator.Validate) *PromotionHandler {
+	return &PromotionHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *PromotionHandler) ListPromotions(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	promotions, err := h.UseCase.ListPromotions(ctx.Request().Context(), sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, promotions)
+}
+
+func (h *PromotionHandler) CreatePromotion(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.PromotionPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	promotion, err := h.UseCase.CreatePromotion(ctx.Request().Context(), sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusCreated, promotion)
+}
+
+func (h *PromotionHandler) UpdatePromotion(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("promotionId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var payload = new(dto.PromotionUpdatePayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	promotion, err := h.UseCase.UpdatePromotion(ctx.Request().Context(), id, sellerID, payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, promotion)
+}
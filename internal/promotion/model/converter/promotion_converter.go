@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"strconv"
+	"tutup-lapak/internal/promotion/dto"
+	"tutup-lapak/internal/promotion/model"
+)
+
+func ToPromotionResponse(promotion model.Promotion) dto.PromotionResponse {
+	var productID *string
+	if promotion.ProductID != nil {
+		id := strconv.Itoa(*promotion.ProductID)
+		productID = &id
+	}
+
+	return dto.PromotionResponse{
+		PromotionID:   strconv.Itoa(promotion.ID),
+		Name:          promotion.Name,
+		Code:          promotion.Code,
+		DiscountType:  promotion.DiscountType,
+		DiscountValue: promotion.DiscountValue,
+		MaxDiscount:   promotion.MaxDiscount,
+		ProductID:     productID,
+		Category:      promotion.Category,
+		MinSpend:      promotion.MinSpend,
+		UsageLimit:    promotion.UsageLimit,
+		UsageCount:    promotion.UsageCount,
+		StartsAt:      promotion.StartsAt,
+		EndsAt:        promotion.EndsAt,
+		IsActive:      promotion.IsActive,
+		CreatedAt:     promotion.CreatedAt,
+		UpdatedAt:     promotion.UpdatedAt,
+	}
+}
+
+func ToPromotionResponses(promotions []model.Promotion) []dto.PromotionResponse {
+	responses := make([]dto.PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		responses = append(responses, ToPromotionResponse(promotion))
+	}
+	return responses
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+type Promotion struct {
+	ID            int
+	SellerID      int
+	Name          string
+	Code          *string
+	DiscountType  string
+	DiscountValue int
+	MaxDiscount   *int
+	ProductID     *int
+	Category      *string
+	MinSpend      int
+	UsageLimit    *int
+	UsageCount    int
+	StartsAt      *time.Time
+	EndsAt        *time.Time
+	IsActive      bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CartLine is the amount spent on one product of a seller in a checkout.
+// Categories holds the category of the product and every category above it.
+type CartLine struct {
+	ProductID  int
+	Categories []string
+	Amount     int
+}
+
+// Available tells whether the promotion can still be redeemed at now.
+func (p Promotion) Available(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return p.UsageLimit == nil || p.UsageCount < *p.UsageLimit
+}
+
+// EligibleSpend sums the lines covered by the promotion. A category
+// promotion covers the products of its category and of the ones below it.
+func (p Promotion) EligibleSpend(lines []CartLine) int {
+	spend := 0
+	for _, line := range lines {
+		if p.ProductID != nil && *p.ProductID != line.ProductID {
+			continue
+		}
+		if p.Category != nil && !slices.Contains(line.Categories, *p.Category) {
+			continue
+		}
+		spend += line.Amount
+	}
+	return spend
+}
+
+// Discount returns the amount taken off lines, which must all belong to the
+// seller of the promotion. It is zero below the minimum spend and never
+// exceeds the eligible spend.
+func (p Promotion) Discount(lines []CartLine) int {
+	spend := p.EligibleSpend(lines)
+	if spend == 0 || spend < p.MinSpend {
+		return 0
+	}
+
+	discount := p.DiscountValue
+	if p.DiscountType == DiscountPercentage {
+		discount = spend * p.DiscountValue / 100
+	}
+	if p.MaxDiscount != nil {
+		discount = min(discount, *p.MaxDiscount)
+	}
+	return min(discount, spend)
+}
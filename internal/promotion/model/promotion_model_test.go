@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int          { return &v }
+func stringPtr(v string) *string { return &v }
+
+func TestPromotionEligibleSpend(t *testing.T) {
+	lines := []CartLine{
+		{ProductID: 1, Categories: []string{"Coffee", "Beverage"}, Amount: 30000},
+		{ProductID: 2, Categories: []string{"Beverage"}, Amount: 10000},
+		{ProductID: 3, Categories: []string{"Tools"}, Amount: 5000},
+	}
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      int
+	}{
+		{name: "whole catalog", promotion: Promotion{}, want: 45000},
+		{name: "one product", promotion: Promotion{ProductID: intPtr(2)}, want: 10000},
+		{name: "product not in cart", promotion: Promotion{ProductID: intPtr(9)}, want: 0},
+		{name: "category covers the ones below", promotion: Promotion{Category: stringPtr("Beverage")}, want: 40000},
+		{name: "subcategory", promotion: Promotion{Category: stringPtr("Coffee")}, want: 30000},
+		{name: "category not in cart", promotion: Promotion{Category: stringPtr("Food")}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.EligibleSpend(lines); got != tt.want {
+				t.Errorf("EligibleSpend() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	lines := []CartLine{{ProductID: 1, Amount: 9999}}
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      int
+	}{
+		{name: "percentage truncates", promotion: Promotion{DiscountType: DiscountPercentage, DiscountValue: 10}, want: 999},
+		{name: "percentage capped by max discount", promotion: Promotion{DiscountType: DiscountPercentage, DiscountValue: 50, MaxDiscount: intPtr(2000)}, want: 2000},
+		{name: "fixed", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 1500}, want: 1500},
+		{name: "fixed capped by max discount", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 1500, MaxDiscount: intPtr(1000)}, want: 1000},
+		{name: "fixed never exceeds the spend", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 20000}, want: 9999},
+		{name: "min spend reached", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 500, MinSpend: 9999}, want: 500},
+		{name: "below min spend", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 500, MinSpend: 10000}, want: 0},
+		{name: "nothing eligible", promotion: Promotion{DiscountType: DiscountFixed, DiscountValue: 500, ProductID: intPtr(2)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.Discount(lines); got != tt.want {
+				t.Errorf("Discount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionAvailable(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      bool
+	}{
+		{name: "running", promotion: Promotion{IsActive: true, StartsAt: &before, EndsAt: &after}, want: true},
+		{name: "inactive", promotion: Promotion{IsActive: false}, want: false},
+		{name: "not started", promotion: Promotion{IsActive: true, StartsAt: &after}, want: false},
+		{name: "ended", promotion: Promotion{IsActive: true, EndsAt: &now}, want: false},
+		{name: "under usage limit", promotion: Promotion{IsActive: true, UsageLimit: intPtr(2), UsageCount: 1}, want: true},
+		{name: "usage limit reached", promotion: Promotion{IsActive: true, UsageLimit: intPtr(2), UsageCount: 2}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.Available(now); got != tt.want {
+				t.Errorf("Available() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
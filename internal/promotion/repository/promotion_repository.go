@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"tutup-lapak/internal/promotion/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PromotionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPromotionRepository(pool *pgxpool.Pool) *PromotionRepository {
+	return &PromotionRepository{pool: pool}
+}
+
+var promotionConstraints = map[string]string{
+	"idx_promotions_code": "voucher code already exists",
+}
+
+const listPromotionsQuery = `-- name: ListPromotions :many
+SELECT id, seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, usage_count, starts_at, ends_at, is_active, created_at, updated_at
+FROM promotions
+WHERE seller_id = $1
+ORDER BY created_at DESC
+`
+
+func (r *PromotionRepository) ListPromotions(ctx context.Context, sellerID int) ([]model.Promotion, error) {
+	rows, err := r.pool.Query(ctx, listPromotionsQuery, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	return collectPromotions(rows)
+}
+
+const getPromotionQuery = `-- name: GetPromotion :one
+SELECT id, seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, usage_count, starts_at, ends_at, is_active, created_at, updated_at
+FROM promotions
+WHERE id = $1 AND seller_id = $2
+`
+
+func (r *PromotionRepository) GetPromotion(ctx context.Context, id, sellerID int) (model.Promotion, error) {
+	row := r.pool.QueryRow(ctx, getPromotionQuery, id, sellerID)
+	return scanPromotion(row)
+}
+
+const listApplicablePromotionsQuery = `-- name: ListApplicablePromotions :many
+SELECT id, seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, usage_count, starts_at, ends_at, is_active, created_at, updated_at
+FROM promotions
+WHERE (
+		code IS NULL AND seller_id = ANY($1::TEXT[]::BIGINT[]) AND is_active
+		AND (starts_at IS NULL OR starts_at <= $3) AND (ends_at IS NULL OR ends_at > $3)
+		AND (usage_limit IS NULL OR usage_count < usage_limit)
+	)
+	OR code = ANY($2::TEXT[])
+`
+
+// ListApplicablePromotions returns the automatic promotions of sellerIDs
+// running at now, and the vouchers of codes whatever their state so a
+// checkout can tell why a code does not apply.
+func (r *PromotionRepository) ListApplicablePromotions(ctx context.Context, sellerIDs, codes []string, now time.Time) ([]model.Promotion, error) {
+	rows, err := r.pool.Query(ctx, listApplicablePromotionsQuery, sellerIDs, codes, now)
+	if err != nil {
+		return nil, err
+	}
+	return collectPromotions(rows)
+}
+
+const createPromotionQuery = `-- name: CreatePromotion :one
+INSERT INTO promotions (
+	seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, starts_at, ends_at, is_active
+)
+SELECT $1::BIGINT, $2::VARCHAR, $3::VARCHAR, $4::enum_promotion_discount_types, $5::INT, $6::INT, $7::BIGINT, $8::VARCHAR, $9::INT, $10::INT, $11::TIMESTAMPTZ, $12::TIMESTAMPTZ, $13::BOOLEAN
+WHERE $7::BIGINT IS NULL OR EXISTS (
+	SELECT 1 FROM products WHERE id = $7::BIGINT AND seller_id = $1::BIGINT AND deleted_at IS NULL
+)
+RETURNING id, seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, usage_count, starts_at, ends_at, is_active, created_at, updated_at
+`
+
+type CreatePromotionParams struct {
+	SellerID      int
+	Name          string
+	Code          *string
+	DiscountType  string
+	DiscountValue int
+	MaxDiscount   *int
+	ProductID     *int
+	Category      *string
+	MinSpend      int
+	UsageLimit    *int
+	StartsAt      *time.Time
+	EndsAt        *time.Time
+	IsActive      bool
+}
+
+// CreatePromotion returns ErrNotFound when ProductID is not a product of the
+// seller.
+func (r *PromotionRepository) CreatePromotion(ctx context.Context, arg CreatePromotionParams) (model.Promotion, error) {
+	row := r.pool.QueryRow(ctx, createPromotionQuery,
+		arg.SellerID,
+		arg.Name,
+		arg.Code,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.MaxDiscount,
+		arg.ProductID,
+		arg.Category,
+		arg.MinSpend,
+		arg.UsageLimit,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.IsActive,
+	)
+	promotion, err := scanPromotion(row)
+	if err != nil {
+		return model.Promotion{}, customErrors.HandlePgConstraintError(err, "failed to create promotion", promotionConstraints)
+	}
+	return promotion, nil
+}
+
+const updatePromotionQuery = `-- name: UpdatePromotion :one
+UPDATE promotions
+SET
+	name = COALESCE($3, name),
+	max_discount = COALESCE($4, max_discount),
+	min_spend = COALESCE($5, min_spend),
+	usage_limit = COALESCE($6, usage_limit),
+	starts_at = COALESCE($7, starts_at),
+	ends_at = COALESCE($8, ends_at),
+	is_active = COALESCE($9, is_active)
+WHERE id = $1 AND seller_id = $2
+RETURNING id, seller_id, name, code, discount_type, discount_value, max_discount, product_id, category, min_spend, usage_limit, usage_count, starts_at, ends_at, is_active, created_at, updated_at
+`
+
+type UpdatePromotionParams struct {
+	ID          int
+	SellerID    int
+	Name        *string
+	MaxDiscount *int
+	MinSpend    *int
+	UsageLimit  *int
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	IsActive    *bool
+}
+
+func (r *PromotionRepository) UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (model.Promotion, error) {
+	row := r.pool.QueryRow(ctx, updatePromotionQuery,
+		arg.ID,
+		arg.SellerID,
+		arg.Name,
+		arg.MaxDiscount,
+		arg.MinSpend,
+		arg.UsageLimit,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.IsActive,
+	)
+	promotion, err := scanPromotion(row)
+	if err != nil {
+		return model.Promotion{}, customErrors.HandlePgConstraintError(err, "failed to update promotion", promotionConstraints)
+	}
+	return promotion, nil
+}
+
+func collectPromotions(rows pgx.Rows) ([]model.Promotion, error) {
+	defer rows.Close()
+
+	var items []model.Promotion
+	for rows.Next() {
+		i, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanPromotion(row pgx.Row) (model.Promotion, error) {
+	var i model.Promotion
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.Name,
+		&i.Code,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.MaxDiscount,
+		&i.ProductID,
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.UsageCount,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
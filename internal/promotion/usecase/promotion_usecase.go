@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	categoryRepository "tutup-lapak/internal/category/repository"
+	"tutup-lapak/internal/promotion/dto"
+	"tutup-lapak/internal/promotion/model"
+	"tutup-lapak/internal/promotion/model/converter"
+	"tutup-lapak/internal/promotion/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+type PromotionUsecase struct {
+	repo         *repository.PromotionRepository
+	categoryRepo *categoryRepository.CategoryRepository
+}
+
+func NewPromotionUsecase(repo *repository.PromotionRepository, categoryRepo *categoryRepository.CategoryRepository) *PromotionUsecase {
+	return &PromotionUsecase{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (u *PromotionUsecase) ListPromotions(ctx context.Context, sellerID int) ([]dto.PromotionResponse, error) {
+	promotions, err := u.repo.ListPromotions(ctx, sellerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get promotions")
+	}
+
+	return converter.ToPromotionResponses(promotions), nil
+}
+
+// CreatePromotion stores voucher codes in upper case, codes are matched
+// case insensitively at checkout.
+func (u *PromotionUsecase) CreatePromotion(ctx context.Context, sellerID int, payload *dto.PromotionPayload) (*dto.PromotionResponse, error) {
+	if payload.DiscountType == model.DiscountPercentage && payload.DiscountValue > 100 {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "a percentage discount is at most 100")
+	}
+	if payload.StartsAt != nil && payload.EndsAt != nil && !payload.EndsAt.After(*payload.StartsAt) {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "endsAt must be after startsAt")
+	}
+
+	arg := repository.CreatePromotionParams{
+		SellerID:      sellerID,
+		Name:          payload.Name,
+		DiscountType:  payload.DiscountType,
+		DiscountValue: payload.DiscountValue,
+		MaxDiscount:   payload.MaxDiscount,
+		MinSpend:      payload.MinSpend,
+		UsageLimit:    payload.UsageLimit,
+		StartsAt:      payload.StartsAt,
+		EndsAt:        payload.EndsAt,
+		IsActive:      payload.IsActive == nil || *payload.IsActive,
+	}
+
+	if payload.Code != nil {
+		code := strings.ToUpper(*payload.Code)
+		arg.Code = &code
+	}
+
+	if payload.ProductID != nil {
+		productID, err := strconv.Atoi(*payload.ProductID)
+		if err != nil {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "product not found")
+		}
+		arg.ProductID = &productID
+	}
+
+	if payload.Category != nil {
+		categories, err := u.categoryRepo.GetCategoriesByNames(ctx, []string{*payload.Category})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get category")
+		}
+		if len(categories) == 0 {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "category not exists")
+		}
+		arg.Category = &categories[0].Name
+	}
+
+	promotion, err := u.repo.CreatePromotion(ctx, arg)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "product not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToPromotionResponse(promotion)
+	return &response, nil
+}
+
+// UpdatePromotion keeps the code, discount and scope of a promotion, which
+// redeemed purchases refer to.
+func (u *PromotionUsecase) UpdatePromotion(ctx context.Context, ID, sellerID int, payload *dto.PromotionUpdatePayload) (*dto.PromotionResponse, error) {
+	current, err := u.repo.GetPromotion(ctx, ID, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "promotion not found")
+		}
+		return nil, errors.Wrap(err, "failed to get promotion")
+	}
+
+	startsAt, endsAt := current.StartsAt, current.EndsAt
+	if payload.StartsAt != nil {
+		startsAt = payload.StartsAt
+	}
+	if payload.EndsAt != nil {
+		endsAt = payload.EndsAt
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "endsAt must be after startsAt")
+	}
+	if payload.UsageLimit != nil && *payload.UsageLimit < current.UsageCount {
+		return nil, errors.Wrapf(customErrors.ErrBadRequest, "usageLimit cannot be below the %d redemptions made", current.UsageCount)
+	}
+
+	arg := repository.UpdatePromotionParams{
+		ID:          ID,
+		SellerID:    sellerID,
+		Name:        payload.Name,
+		MaxDiscount: payload.MaxDiscount,
+		MinSpend:    payload.MinSpend,
+		UsageLimit:  payload.UsageLimit,
+		StartsAt:    payload.StartsAt,
+		EndsAt:      payload.EndsAt,
+		IsActive:    payload.IsActive,
+	}
+
+	promotion, err := u.repo.UpdatePromotion(ctx, arg)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "promotion not found")
+		}
+		return nil, err
+	}
+
+	response := converter.ToPromotionResponse(promotion)
+	return &response, nil
+}
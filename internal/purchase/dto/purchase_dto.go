@@ -1,12 +1,14 @@
 package dto
 
 import (
+	"time"
 	"tutup-lapak/internal/product/dto"
 )
 
 type ProductPurchaseRequest struct {
-	ProductID string `json:"productId" validate:"required"`
-	Qty       int    `json:"qty" validate:"required,min=1"`
+	ProductID string  `json:"productId" validate:"required"`
+	VariantID *string `json:"variantId" validate:"omitnil,number"`
+	Qty       int     `json:"qty" validate:"required,min=1"`
 }
 
 type PurchaseRequest struct {
@@ -14,6 +16,7 @@ type PurchaseRequest struct {
 	SenderName          string                   `json:"senderName" validate:"required,min=4,max=55"`
 	SenderContactType   string                   `json:"senderContactType" validate:"required,oneof=email phone"`
 	SenderContactDetail string                   `json:"senderContactDetail" validate:"required,contact_detail_validator"`
+	VoucherCodes        []string                 `json:"voucherCodes" validate:"omitempty,max=5,dive,min=3,max=32"`
 }
 
 type PaymentRequest struct {
@@ -22,15 +25,49 @@ type PaymentRequest struct {
 
 type PaymentDetail struct {
 	SellerId          string `json:"sellerId"`
+	BankAccountID     string `json:"bankAccountId,omitempty"`
 	BankAccountName   string `json:"bankAccountName"`
 	BankAccountHolder string `json:"bankAccountHolder"`
 	BankAccountNumber string `json:"bankAccountNumber"`
+	SubtotalPrice     int    `json:"subtotalPrice"`
+	Discount          int    `json:"discount"`
 	TotalPrice        int    `json:"totalPrice"`
 }
 
+// DiscountDetail is a promotion redeemed on the products of one seller.
+type DiscountDetail struct {
+	PromotionID string  `json:"promotionId"`
+	SellerID    string  `json:"sellerId"`
+	Name        string  `json:"name"`
+	Code        *string `json:"code"`
+	Amount      int     `json:"amount"`
+}
+
 type PurchaseResponse struct {
 	PurchaseID     string                `json:"purchaseId"`
 	PurchasedItems []dto.ProductResponse `json:"purchasedItems"`
+	SubtotalPrice  int                   `json:"subtotalPrice"`
+	Discount       int                   `json:"discount"`
+	Discounts      []DiscountDetail      `json:"discounts"`
 	TotalPrice     int                   `json:"totalPrice"`
 	PaymentDetails []PaymentDetail       `json:"paymentDetails"`
 }
+
+type PurchaseListPayload struct {
+	Limit    int     `query:"limit" validate:"omitempty,number,min=0"`
+	Offset   int     `query:"offset" validate:"omitempty,number,min=0"`
+	SellerID *string `query:"sellerId" validate:"omitempty,number,min=1"`
+	Paid     *bool   `query:"paid"`
+}
+
+type PurchaseDetailResponse struct {
+	PurchaseID          string          `json:"purchaseId"`
+	SubtotalPrice       int             `json:"subtotalPrice"`
+	Discount            int             `json:"discount"`
+	TotalPrice          int             `json:"totalPrice"`
+	SenderName          string          `json:"senderName"`
+	SenderContactType   string          `json:"senderContactType"`
+	SenderContactDetail string          `json:"senderContactDetail"`
+	PaidAt              *time.Time      `json:"paidAt"`
+	PaymentDetails      []PaymentDetail `json:"paymentDetails"`
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+const DEFAULT_LIMIT = 5
+
 type PurchaseHandler struct {
 	UseCase  *usecase.PurchaseUseCase
 	Validate *validator.Validate
@@ -77,3 +79,28 @@ func (h *PurchaseHandler) CreatePayment(ctx echo.Context) error {
 		Message: "Successfully received payment",
 	})
 }
+
+func (h *PurchaseHandler) ListPurchases(ctx echo.Context) error {
+	var payload = new(dto.PurchaseListPayload)
+
+	if err := ctx.Bind(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if payload.Limit == 0 {
+		payload.Limit = DEFAULT_LIMIT
+	}
+
+	purchases, err := h.UseCase.ListPurchases(ctx.Request().Context(), payload)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, purchases)
+}
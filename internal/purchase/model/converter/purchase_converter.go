@@ -7,11 +7,42 @@ import (
 	"tutup-lapak/internal/purchase/model"
 )
 
-func ToPurchaseResponse(purchase model.Purchase, purchasedItems []productDto.ProductResponse, paymentDetails []dto.PaymentDetail) dto.PurchaseResponse {
+func ToPurchaseResponse(purchase model.Purchase, purchasedItems []productDto.ProductResponse, paymentDetails []dto.PaymentDetail, discounts []dto.DiscountDetail) dto.PurchaseResponse {
 	return dto.PurchaseResponse{
 		PurchaseID:     strconv.Itoa(purchase.ID),
+		SubtotalPrice:  purchase.SubtotalPrice,
+		Discount:       purchase.Discount,
+		Discounts:      discounts,
 		TotalPrice:     purchase.TotalPrice,
 		PurchasedItems: purchasedItems,
 		PaymentDetails: paymentDetails,
 	}
 }
+
+func ToPurchaseDetailResponse(purchase model.Purchase, paymentDetails []model.PurchasePaymentDetail) dto.PurchaseDetailResponse {
+	details := make([]dto.PaymentDetail, 0, len(paymentDetails))
+	for _, detail := range paymentDetails {
+		details = append(details, dto.PaymentDetail{
+			SellerId:          detail.SellerID,
+			BankAccountID:     detail.BankAccountID,
+			BankAccountName:   detail.BankAccountName,
+			BankAccountHolder: detail.BankAccountHolder,
+			BankAccountNumber: detail.BankAccountNumber,
+			SubtotalPrice:     detail.SubtotalPrice,
+			Discount:          detail.Discount,
+			TotalPrice:        detail.TotalPrice,
+		})
+	}
+
+	return dto.PurchaseDetailResponse{
+		PurchaseID:          strconv.Itoa(purchase.ID),
+		SubtotalPrice:       purchase.SubtotalPrice,
+		Discount:            purchase.Discount,
+		TotalPrice:          purchase.TotalPrice,
+		SenderName:          purchase.SenderName,
+		SenderContactType:   purchase.SenderContactType,
+		SenderContactDetail: purchase.SenderContactDetail,
+		PaidAt:              purchase.PaidAt,
+		PaymentDetails:      details,
+	}
+}
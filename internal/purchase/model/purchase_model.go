@@ -4,6 +4,8 @@ import "time"
 
 type Purchase struct {
 	ID                  int
+	SubtotalPrice       int
+	Discount            int
 	TotalPrice          int
 	TotalTransfer       int
 	SenderName          string
@@ -16,6 +18,19 @@ type PurchaseProduct struct {
 	ID         int
 	PurchaseID int
 	ProductID  int
+	VariantID  *int
 	Qty        int
 	CreatedAt  time.Time
 }
+
+type PurchasePaymentDetail struct {
+	PurchaseID        int
+	SellerID          string
+	BankAccountID     string
+	BankAccountName   string
+	BankAccountHolder string
+	BankAccountNumber string
+	SubtotalPrice     int
+	Discount          int
+	TotalPrice        int
+}
@@ -6,9 +6,11 @@ import (
 
 	"tutup-lapak/internal/purchase/dto"
 	"tutup-lapak/internal/purchase/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
 )
 
 type PurchaseRepository struct {
@@ -21,23 +23,58 @@ func NewPurchaseRepository(pool *pgxpool.Pool) *PurchaseRepository {
 
 const createPurchaseQuery = `-- name: CreatePurchase :one
 INSERT INTO purchases (
-  total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at
+  subtotal_price, discount, total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at
 ) VALUES (
-  $1, $2, $3, $4, $5, NULL
-) RETURNING id, total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at
+  $1, $2, $3, $4, $5, $6, $7, NULL
+) RETURNING id, subtotal_price, discount, total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at
 `
 
 const insertPurchaseProductsQuery = `-- name: InsertPurchaseProducts :exec
-INSERT INTO pivot_purchase_products (purchase_id, product_id, qty) VALUES ($1, $2, $3)
+INSERT INTO pivot_purchase_products (purchase_id, product_id, variant_id, qty) VALUES ($1, $2, $3::BIGINT, $4)
+`
+
+const insertPurchasePaymentDetailsQuery = `-- name: InsertPurchasePaymentDetails :exec
+INSERT INTO purchase_payment_details (
+  purchase_id, seller_id, bank_account_id, bank_account_name, bank_account_holder, bank_account_number, subtotal_price, discount, total_price
+) VALUES (
+  $1, $2, NULLIF($3, '')::BIGINT, $4, $5, $6, $7, $8, $9
+)
+`
+
+// Redeeming locks the promotion row, so concurrent payments recheck the
+// usage limit against the count committed before them.
+const redeemPromotionQuery = `-- name: RedeemPromotion :execrows
+UPDATE promotions
+SET usage_count = usage_count + 1
+WHERE id = $1 AND is_active
+  AND (starts_at IS NULL OR starts_at <= NOW())
+  AND (ends_at IS NULL OR ends_at > NOW())
+  AND (usage_limit IS NULL OR usage_count < usage_limit)
+`
+
+// Promotions are listed by ID, so payments sharing promotions lock them in
+// the same order. Discounts of deleted promotions have nothing to redeem.
+const listPurchasePromotionsQuery = `-- name: ListPurchasePromotions :many
+SELECT promotion_id, name FROM purchase_discounts
+WHERE purchase_id = $1 AND promotion_id IS NOT NULL
+ORDER BY promotion_id
+`
+
+const insertPurchaseDiscountQuery = `-- name: InsertPurchaseDiscount :exec
+INSERT INTO purchase_discounts (purchase_id, promotion_id, seller_id, name, code, amount) VALUES ($1, $2, $3, $4, $5, $6)
 `
 
 type CreatePurchaseParams struct {
+	SubtotalPrice       int
+	Discount            int
 	TotalPrice          int
 	TotalTransfer       int
 	SenderName          string
 	SenderContactType   string
 	SenderContactDetail string
 	PurchasedItems      []dto.ProductPurchaseRequest
+	PaymentDetails      []dto.PaymentDetail
+	Discounts           []dto.DiscountDetail
 }
 
 func (r *PurchaseRepository) CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (model.Purchase, error) {
@@ -48,6 +85,8 @@ func (r *PurchaseRepository) CreatePurchase(ctx context.Context, arg CreatePurch
 	defer tx.Rollback(ctx)
 
 	row := tx.QueryRow(ctx, createPurchaseQuery,
+		arg.SubtotalPrice,
+		arg.Discount,
 		arg.TotalPrice,
 		arg.TotalTransfer,
 		arg.SenderName,
@@ -58,6 +97,8 @@ func (r *PurchaseRepository) CreatePurchase(ctx context.Context, arg CreatePurch
 	var purchase model.Purchase
 	err = row.Scan(
 		&purchase.ID,
+		&purchase.SubtotalPrice,
+		&purchase.Discount,
 		&purchase.TotalPrice,
 		&purchase.TotalTransfer,
 		&purchase.SenderName,
@@ -71,7 +112,31 @@ func (r *PurchaseRepository) CreatePurchase(ctx context.Context, arg CreatePurch
 
 	batch := &pgx.Batch{}
 	for _, item := range arg.PurchasedItems {
-		batch.Queue(insertPurchaseProductsQuery, purchase.ID, item.ProductID, item.Qty)
+		batch.Queue(insertPurchaseProductsQuery, purchase.ID, item.ProductID, item.VariantID, item.Qty)
+	}
+	// Snapshot the payout account so later edits don't rewrite history
+	for _, detail := range arg.PaymentDetails {
+		batch.Queue(insertPurchasePaymentDetailsQuery,
+			purchase.ID,
+			detail.SellerId,
+			detail.BankAccountID,
+			detail.BankAccountName,
+			detail.BankAccountHolder,
+			detail.BankAccountNumber,
+			detail.SubtotalPrice,
+			detail.Discount,
+			detail.TotalPrice,
+		)
+	}
+	for _, discount := range arg.Discounts {
+		batch.Queue(insertPurchaseDiscountQuery,
+			purchase.ID,
+			discount.PromotionID,
+			discount.SellerID,
+			discount.Name,
+			discount.Code,
+			discount.Amount,
+		)
 	}
 	br := tx.SendBatch(ctx, batch)
 	if err := br.Close(); err != nil {
@@ -86,7 +151,7 @@ func (r *PurchaseRepository) CreatePurchase(ctx context.Context, arg CreatePurch
 }
 
 const getPurchase = `-- name: GetPurchase :one
-SELECT id, total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at FROM purchases
+SELECT id, subtotal_price, discount, total_price, total_transfer, sender_name, sender_contact_type, sender_contact_detail, paid_at FROM purchases
 WHERE id = $1
 LIMIT 1
 `
@@ -96,6 +161,8 @@ func (r *PurchaseRepository) GetPurchase(ctx context.Context, purchaseId int) (m
 	var i model.Purchase
 	err := row.Scan(
 		&i.ID,
+		&i.SubtotalPrice,
+		&i.Discount,
 		&i.TotalPrice,
 		&i.TotalTransfer,
 		&i.SenderName,
@@ -107,7 +174,7 @@ func (r *PurchaseRepository) GetPurchase(ctx context.Context, purchaseId int) (m
 }
 
 const getPurchaseProductsByIdQuery = `-- name: GetPurchaseProducts :many
-SELECT id, purchase_id, product_id, qty, created_at FROM pivot_purchase_products
+SELECT id, purchase_id, product_id, variant_id, qty, created_at FROM pivot_purchase_products
 WHERE purchase_id = $1
 `
 
@@ -126,6 +193,7 @@ func (r *PurchaseRepository) GetPurchaseProductsById(ctx context.Context, purcha
 			&i.ID,
 			&i.PurchaseID,
 			&i.ProductID,
+			&i.VariantID,
 			&i.Qty,
 			&i.CreatedAt,
 		); err != nil {
@@ -142,11 +210,32 @@ func (r *PurchaseRepository) GetPurchaseProductsById(ctx context.Context, purcha
 const updatePurchasePaidAtQuery = `-- name: UpdatePurchasePaidAt :exec
 UPDATE purchases
 SET paid_at = $1
-WHERE id = $2
+WHERE id = $2 AND paid_at IS NULL
 `
 
 const updateProductQtyQuery = `-- name: UpdateProductQty :exec
-UPDATE products SET qty = qty - $1 WHERE id = $2
+UPDATE products SET qty = qty - $1 WHERE id = $2 AND qty >= $1
+`
+
+// The qty of a product with variants follows its variants through the
+// rollup_product_variants trigger.
+const updateVariantQtyQuery = `-- name: UpdateVariantQty :exec
+UPDATE product_variants SET qty = qty - $1 WHERE id = $2 AND qty >= $1
+`
+
+// Products of the purchase whose qty went from above their threshold to at
+// most it. The qty is already decremented, so qty + sold is the qty before.
+const createStockAlertsQuery = `-- name: CreateStockAlerts :exec
+INSERT INTO stock_alerts (seller_id, product_id, purchase_id, qty, threshold)
+SELECT p.seller_id, p.id, $1, p.qty, p.low_stock_threshold
+FROM products p
+JOIN (
+	SELECT product_id, SUM(qty) AS qty
+	FROM pivot_purchase_products
+	WHERE purchase_id = $1
+	GROUP BY product_id
+) sold ON sold.product_id = p.id
+WHERE p.qty <= p.low_stock_threshold AND p.qty + sold.qty > p.low_stock_threshold
 `
 
 type UpdatePurchaseParams struct {
@@ -154,6 +243,9 @@ type UpdatePurchaseParams struct {
 	PurchaseProducts []model.PurchaseProduct
 }
 
+// UpdatePurchase marks the purchase paid, redeems its promotions and takes
+// its items out of stock. The stock alerts raised by the new quantities are
+// left for the alert dispatcher to deliver.
 func (r *PurchaseRepository) UpdatePurchase(ctx context.Context, arg UpdatePurchaseParams) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
@@ -161,17 +253,38 @@ func (r *PurchaseRepository) UpdatePurchase(ctx context.Context, arg UpdatePurch
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, updatePurchasePaidAtQuery, time.Now(), arg.PurchaseID)
+	// only the first payment takes the items out of stock, a repeated or
+	// concurrent one waits on the row lock and then finds it paid
+	result, err := tx.Exec(ctx, updatePurchasePaidAtQuery, time.Now(), arg.PurchaseID)
 	if err != nil {
 		return err
 	}
+	if result.RowsAffected() != 1 {
+		return errors.Wrap(customErrors.ErrConflict, "purchase already paid")
+	}
+
+	if err := redeemPromotions(ctx, tx, arg.PurchaseID); err != nil {
+		return err
+	}
 
 	batch := &pgx.Batch{}
 	for _, product := range arg.PurchaseProducts {
+		if product.VariantID != nil {
+			batch.Queue(updateVariantQtyQuery, product.Qty, *product.VariantID)
+			continue
+		}
 		batch.Queue(updateProductQtyQuery, product.Qty, product.ProductID)
 	}
 	br := tx.SendBatch(ctx, batch)
-	if err := br.Close(); err != nil {
+	err = checkStockUpdates(br, batch.Len())
+	if closeErr := br.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, createStockAlertsQuery, arg.PurchaseID); err != nil {
 		return err
 	}
 
@@ -181,3 +294,140 @@ func (r *PurchaseRepository) UpdatePurchase(ctx context.Context, arg UpdatePurch
 
 	return nil
 }
+
+// redeemPromotions counts the promotions discounting the purchase as used.
+// Checkouts only quote discounts, so unpaid purchases can't use up a limit.
+func redeemPromotions(ctx context.Context, tx pgx.Tx, purchaseID int) error {
+	rows, err := tx.Query(ctx, listPurchasePromotionsQuery, purchaseID)
+	if err != nil {
+		return err
+	}
+	type promotion struct {
+		ID   int
+		Name string
+	}
+	promotions, err := pgx.CollectRows(rows, pgx.RowToStructByPos[promotion])
+	if err != nil {
+		return err
+	}
+
+	for _, promotion := range promotions {
+		result, err := tx.Exec(ctx, redeemPromotionQuery, promotion.ID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() != 1 {
+			return errors.Wrapf(customErrors.ErrConflict, "promotion %s is no longer available", promotion.Name)
+		}
+	}
+	return nil
+}
+
+// checkStockUpdates reads the results of the stock batch, a product or variant
+// that is left untouched had less stock than the purchase takes.
+func checkStockUpdates(br pgx.BatchResults, updates int) error {
+	for range updates {
+		result, err := br.Exec()
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() != 1 {
+			return errors.Wrap(customErrors.ErrConflict, "The requested quantity is unavailable or exceeds the available stock.")
+		}
+	}
+	return nil
+}
+
+const listPurchasesQuery = `-- name: ListPurchases :many
+SELECT pu.id, pu.subtotal_price, pu.discount, pu.total_price, pu.total_transfer, pu.sender_name, pu.sender_contact_type, pu.sender_contact_detail, pu.paid_at
+FROM purchases pu
+WHERE
+	($1::BIGINT IS NULL OR EXISTS (
+		SELECT 1 FROM pivot_purchase_products ppp
+		JOIN products p ON p.id = ppp.product_id
+		WHERE ppp.purchase_id = pu.id AND p.seller_id = $1::BIGINT
+	))
+	AND ($2::BOOLEAN IS NULL OR (pu.paid_at IS NOT NULL) = $2::BOOLEAN)
+ORDER BY pu.id DESC
+LIMIT $3
+OFFSET $4
+`
+
+type ListPurchasesParams struct {
+	SellerID *string
+	Paid     *bool
+	Limit    int
+	Offset   int
+}
+
+func (r *PurchaseRepository) ListPurchases(ctx context.Context, arg ListPurchasesParams) ([]model.Purchase, error) {
+	rows, err := r.pool.Query(ctx, listPurchasesQuery,
+		arg.SellerID,
+		arg.Paid,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.Purchase
+	for rows.Next() {
+		var i model.Purchase
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubtotalPrice,
+			&i.Discount,
+			&i.TotalPrice,
+			&i.TotalTransfer,
+			&i.SenderName,
+			&i.SenderContactType,
+			&i.SenderContactDetail,
+			&i.PaidAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPurchasePaymentDetailsQuery = `-- name: GetPurchasePaymentDetails :many
+SELECT purchase_id, seller_id::TEXT, COALESCE(bank_account_id::TEXT, ''), bank_account_name, bank_account_holder, bank_account_number, subtotal_price, discount, total_price
+FROM purchase_payment_details
+WHERE purchase_id = ANY($1::BIGINT[])
+ORDER BY id
+`
+
+func (r *PurchaseRepository) GetPurchasePaymentDetails(ctx context.Context, purchaseIDs []int) ([]model.PurchasePaymentDetail, error) {
+	rows, err := r.pool.Query(ctx, getPurchasePaymentDetailsQuery, purchaseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.PurchasePaymentDetail
+	for rows.Next() {
+		var i model.PurchasePaymentDetail
+		if err := rows.Scan(
+			&i.PurchaseID,
+			&i.SellerID,
+			&i.BankAccountID,
+			&i.BankAccountName,
+			&i.BankAccountHolder,
+			&i.BankAccountNumber,
+			&i.SubtotalPrice,
+			&i.Discount,
+			&i.TotalPrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	promotionModel "tutup-lapak/internal/promotion/model"
+	"tutup-lapak/internal/purchase/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+// selectDiscounts grants each seller in the cart the largest discount among
+// its running promotions and the voucher entered for it, one per seller. A
+// voucher that cannot apply fails the checkout so the buyer learns why. The
+// result is sorted by promotion ID.
+func (u *PurchaseUseCase) selectDiscounts(ctx context.Context, voucherCodes []string, cartLines map[string][]promotionModel.CartLine) ([]dto.DiscountDetail, error) {
+	codes := make([]string, 0, len(voucherCodes))
+	for _, code := range voucherCodes {
+		code = strings.ToUpper(code)
+		if slices.Contains(codes, code) {
+			return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s is entered twice", code)
+		}
+		codes = append(codes, code)
+	}
+
+	sellerIDs := make([]string, 0, len(cartLines))
+	for sellerID := range cartLines {
+		sellerIDs = append(sellerIDs, sellerID)
+	}
+
+	now := time.Now()
+	promotions, err := u.promotionRepo.ListApplicablePromotions(ctx, sellerIDs, codes, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get promotions")
+	}
+
+	return pickDiscounts(promotions, codes, cartLines, now)
+}
+
+// pickDiscounts makes the choice of selectDiscounts among the promotions
+// listed for the cart and the upper cased voucher codes.
+func pickDiscounts(promotions []promotionModel.Promotion, codes []string, cartLines map[string][]promotionModel.CartLine, now time.Time) ([]dto.DiscountDetail, error) {
+	slices.SortFunc(promotions, func(a, b promotionModel.Promotion) int {
+		return a.ID - b.ID
+	})
+
+	vouchers := make(map[string]string)
+	best := make(map[string]dto.DiscountDetail)
+	for _, promotion := range promotions {
+		sellerID := strconv.Itoa(promotion.SellerID)
+		lines := cartLines[sellerID]
+
+		if promotion.Code != nil {
+			code := *promotion.Code
+			if _, entered := vouchers[sellerID]; entered {
+				return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s cannot be combined with voucher %s", code, vouchers[sellerID])
+			}
+			vouchers[sellerID] = code
+
+			spend := promotion.EligibleSpend(lines)
+			switch {
+			case spend == 0:
+				return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s does not apply to this purchase", code)
+			case !promotion.Available(now):
+				return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s is no longer available", code)
+			case spend < promotion.MinSpend:
+				return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s needs a minimum spend of %d", code, promotion.MinSpend)
+			}
+		}
+
+		amount := promotion.Discount(lines)
+		if amount > best[sellerID].Amount {
+			best[sellerID] = dto.DiscountDetail{
+				PromotionID: strconv.Itoa(promotion.ID),
+				SellerID:    sellerID,
+				Name:        promotion.Name,
+				Code:        promotion.Code,
+				Amount:      amount,
+			}
+		}
+	}
+
+	for _, code := range codes {
+		if !slices.ContainsFunc(promotions, func(p promotionModel.Promotion) bool {
+			return p.Code != nil && *p.Code == code
+		}) {
+			return nil, errors.Wrapf(customErrors.ErrBadRequest, "voucher %s not found", code)
+		}
+	}
+
+	discounts := make([]dto.DiscountDetail, 0, len(best))
+	for _, discount := range best {
+		discounts = append(discounts, discount)
+	}
+	slices.SortFunc(discounts, func(a, b dto.DiscountDetail) int {
+		idA, _ := strconv.Atoi(a.PromotionID)
+		idB, _ := strconv.Atoi(b.PromotionID)
+		return idA - idB
+	})
+
+	return discounts, nil
+}
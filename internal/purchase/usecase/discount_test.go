@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	promotionModel "tutup-lapak/internal/promotion/model"
+	"tutup-lapak/internal/purchase/dto"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+func TestPickDiscounts(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	code := func(v string) *string { return &v }
+	intPtr := func(v int) *int { return &v }
+	promotion := func(id, sellerID int, voucher *string, value int) promotionModel.Promotion {
+		return promotionModel.Promotion{
+			ID:            id,
+			SellerID:      sellerID,
+			Name:          "promo",
+			Code:          voucher,
+			DiscountType:  promotionModel.DiscountFixed,
+			DiscountValue: value,
+			IsActive:      true,
+		}
+	}
+
+	cartLines := map[string][]promotionModel.CartLine{
+		"1": {{ProductID: 10, Amount: 20000}},
+		"2": {{ProductID: 20, Amount: 5000}},
+	}
+
+	tests := []struct {
+		name       string
+		promotions []promotionModel.Promotion
+		codes      []string
+		want       []dto.DiscountDetail
+		wantErr    bool
+	}{
+		{
+			name:       "largest promotion of each seller, sorted by id",
+			promotions: []promotionModel.Promotion{promotion(5, 2, nil, 500), promotion(3, 1, nil, 1000), promotion(4, 1, nil, 2500)},
+			want: []dto.DiscountDetail{
+				{PromotionID: "4", SellerID: "1", Name: "promo", Amount: 2500},
+				{PromotionID: "5", SellerID: "2", Name: "promo", Amount: 500},
+			},
+		},
+		{
+			name:       "voucher beats a smaller running promotion",
+			promotions: []promotionModel.Promotion{promotion(1, 1, nil, 1000), promotion(2, 1, code("HEMAT"), 3000)},
+			codes:      []string{"HEMAT"},
+			want:       []dto.DiscountDetail{{PromotionID: "2", SellerID: "1", Name: "promo", Code: code("HEMAT"), Amount: 3000}},
+		},
+		{
+			name:       "running promotion beats a smaller voucher",
+			promotions: []promotionModel.Promotion{promotion(1, 1, nil, 4000), promotion(2, 1, code("HEMAT"), 3000)},
+			codes:      []string{"HEMAT"},
+			want:       []dto.DiscountDetail{{PromotionID: "1", SellerID: "1", Name: "promo", Amount: 4000}},
+		},
+		{
+			name:       "one voucher per seller",
+			promotions: []promotionModel.Promotion{promotion(1, 1, code("HEMAT"), 1000), promotion(2, 1, code("DISKON"), 2000)},
+			codes:      []string{"HEMAT", "DISKON"},
+			wantErr:    true,
+		},
+		{
+			name: "voucher below min spend",
+			promotions: func() []promotionModel.Promotion {
+				p := promotion(1, 2, code("HEMAT"), 1000)
+				p.MinSpend = 10000
+				return []promotionModel.Promotion{p}
+			}(),
+			codes:   []string{"HEMAT"},
+			wantErr: true,
+		},
+		{
+			name: "voucher for products not in the cart",
+			promotions: func() []promotionModel.Promotion {
+				p := promotion(1, 1, code("HEMAT"), 1000)
+				p.ProductID = intPtr(99)
+				return []promotionModel.Promotion{p}
+			}(),
+			codes:   []string{"HEMAT"},
+			wantErr: true,
+		},
+		{
+			name: "voucher used up",
+			promotions: func() []promotionModel.Promotion {
+				p := promotion(1, 1, code("HEMAT"), 1000)
+				p.UsageLimit, p.UsageCount = intPtr(1), 1
+				return []promotionModel.Promotion{p}
+			}(),
+			codes:   []string{"HEMAT"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown voucher",
+			codes:   []string{"HEMAT"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pickDiscounts(tt.promotions, tt.codes, cartLines, now)
+			if tt.wantErr {
+				if !errors.Is(err, customErrors.ErrBadRequest) {
+					t.Fatalf("pickDiscounts() error = %v, want ErrBadRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pickDiscounts() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pickDiscounts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"strconv"
 
 	productDto "tutup-lapak/internal/product/dto"
+	productModel "tutup-lapak/internal/product/model"
+	productConverter "tutup-lapak/internal/product/model/converter"
 	productRepository "tutup-lapak/internal/product/repository"
+	promotionModel "tutup-lapak/internal/promotion/model"
+	promotionRepository "tutup-lapak/internal/promotion/repository"
 	"tutup-lapak/internal/purchase/dto"
+	"tutup-lapak/internal/purchase/model"
 	"tutup-lapak/internal/purchase/model/converter"
 	"tutup-lapak/internal/purchase/repository"
 	customErrors "tutup-lapak/pkg/custom-errors"
@@ -16,23 +21,28 @@ import (
 )
 
 type PurchaseUseCase struct {
-	purchaseRepo *repository.PurchaseRepository
-	productRepo  *productRepository.ProductRepo
+	purchaseRepo  *repository.PurchaseRepository
+	productRepo   *productRepository.ProductRepo
+	promotionRepo *promotionRepository.PromotionRepository
 }
 
-func NewPurchaseUseCase(purchaseRepo *repository.PurchaseRepository, productRepo *productRepository.ProductRepo) *PurchaseUseCase {
+func NewPurchaseUseCase(purchaseRepo *repository.PurchaseRepository, productRepo *productRepository.ProductRepo, promotionRepo *promotionRepository.PromotionRepository) *PurchaseUseCase {
 	return &PurchaseUseCase{
 		purchaseRepo,
 		productRepo,
+		promotionRepo,
 	}
 }
 
 func (u *PurchaseUseCase) CreatePurchase(ctx context.Context, request *dto.PurchaseRequest) (*dto.PurchaseResponse, error) {
 	var productIDs []int
-	var purchasedQuantityMap = make(map[string]int)
+	var variantIDs []int
+	var distinctProducts = make(map[string]bool)
+	var requestedQuantityMap = make(map[string]int)
 	var paymentDetailsMap = make(map[string]dto.PaymentDetail)
+	var cartLines = make(map[string][]promotionModel.CartLine)
 	var purchasedItems []productDto.ProductResponse
-	totalPrice := 0
+	subtotalPrice := 0
 
 	for _, item := range request.PurchasedItems {
 		productID, err := strconv.Atoi(item.ProductID)
@@ -40,7 +50,15 @@ func (u *PurchaseUseCase) CreatePurchase(ctx context.Context, request *dto.Purch
 			return nil, errors.Wrap(customErrors.ErrBadRequest, "invalid product ID")
 		}
 		productIDs = append(productIDs, productID)
-		purchasedQuantityMap[item.ProductID] = item.Qty
+		distinctProducts[item.ProductID] = true
+
+		if item.VariantID != nil {
+			variantID, err := strconv.Atoi(*item.VariantID)
+			if err != nil {
+				return nil, errors.Wrap(customErrors.ErrBadRequest, "invalid variant ID")
+			}
+			variantIDs = append(variantIDs, variantID)
+		}
 	}
 
 	productData, err := u.productRepo.GetProductsByIDs(ctx, productIDs)
@@ -53,41 +71,107 @@ func (u *PurchaseUseCase) CreatePurchase(ctx context.Context, request *dto.Purch
 		return nil, errors.Wrap(customErrors.ErrBadRequest, "failed to get products")
 	}
 
-	for _, item := range productData {
-		purchasedQuantity := purchasedQuantityMap[item.ProductID]
+	// deleted products are not returned and cannot be purchased
+	if len(productData) != len(distinctProducts) {
+		return nil, errors.Wrap(customErrors.ErrBadRequest, "some products are not available")
+	}
+
+	variantData, err := u.productRepo.GetVariantsByIDs(ctx, variantIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get variants")
+	}
+
+	productsByID := make(map[string]productDto.ProductWithSeller, len(productData))
+	for _, product := range productData {
+		productsByID[product.ProductID] = product
+	}
+	variantsByID := make(map[string]productModel.ProductVariant, len(variantData))
+	for _, variant := range variantData {
+		variantsByID[strconv.Itoa(variant.ID)] = variant
+	}
 
-		if purchasedQuantity > item.Qty {
+	for _, line := range request.PurchasedItems {
+		item, found := productsByID[line.ProductID]
+		if !found {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "some products are not available")
+		}
+		price, available, stockKey := item.Price, item.Qty, line.ProductID
+		purchasedItem := item.ProductResponse
+
+		if line.VariantID != nil {
+			variant, found := variantsByID[*line.VariantID]
+			if !found || strconv.Itoa(variant.ProductID) != line.ProductID {
+				return nil, errors.Wrap(customErrors.ErrBadRequest, "variant not found")
+			}
+
+			price, available, stockKey = variant.Price, variant.Qty, line.ProductID+":"+*line.VariantID
+			variantResponse := productConverter.ToProductVariantResponse(variant)
+			purchasedItem.Variant = &variantResponse
+		} else if item.VariantCount > 0 {
+			return nil, errors.Wrap(customErrors.ErrBadRequest, "variantId is required for products with variants")
+		}
+
+		// the same product or variant may appear on several lines
+		requestedQuantityMap[stockKey] += line.Qty
+		if requestedQuantityMap[stockKey] > available {
 			return nil, errors.Wrap(customErrors.ErrBadRequest, "The requested quantity is unavailable or exceeds the available stock.")
 		}
 
-		purchasedItem := item.ProductResponse
 		purchasedItems = append(purchasedItems, purchasedItem)
 
 		paymentDetail := dto.PaymentDetail{
 			SellerId:          item.SellerId,
+			BankAccountID:     item.BankAccountID,
 			BankAccountName:   item.BankAccountName,
 			BankAccountHolder: item.BankAccountHolder,
 			BankAccountNumber: item.BankAccountNumber,
-			TotalPrice:        item.Price * purchasedQuantity,
+			SubtotalPrice:     price * line.Qty,
+			TotalPrice:        price * line.Qty,
 		}
 		if detail, exists := paymentDetailsMap[item.SellerId]; exists {
+			detail.SubtotalPrice += paymentDetail.SubtotalPrice
 			detail.TotalPrice += paymentDetail.TotalPrice
 			paymentDetailsMap[item.SellerId] = detail // Update map
 		} else {
 			paymentDetailsMap[item.SellerId] = paymentDetail
 		}
 
-		totalPrice += paymentDetail.TotalPrice
+		productID, _ := strconv.Atoi(line.ProductID)
+		cartLines[item.SellerId] = append(cartLines[item.SellerId], promotionModel.CartLine{
+			ProductID:  productID,
+			Categories: item.CategoryPath,
+			Amount:     paymentDetail.SubtotalPrice,
+		})
+
+		subtotalPrice += paymentDetail.SubtotalPrice
+	}
+
+	discounts, err := u.selectDiscounts(ctx, request.VoucherCodes, cartLines)
+	if err != nil {
+		return nil, err
+	}
+
+	totalDiscount := 0
+	for _, discount := range discounts {
+		detail := paymentDetailsMap[discount.SellerID]
+		detail.Discount = discount.Amount
+		detail.TotalPrice -= discount.Amount
+		paymentDetailsMap[discount.SellerID] = detail
+		totalDiscount += discount.Amount
 	}
 	paymentDetails := helper.MapToSlice(paymentDetailsMap)
 
 	arg := repository.CreatePurchaseParams{
-		TotalPrice:          totalPrice,
+		SubtotalPrice:       subtotalPrice,
+		Discount:            totalDiscount,
+		TotalPrice:          subtotalPrice - totalDiscount,
 		TotalTransfer:       len(paymentDetails),
 		SenderName:          request.SenderName,
 		SenderContactType:   request.SenderContactType,
 		SenderContactDetail: request.SenderContactDetail,
 		PurchasedItems:      request.PurchasedItems,
+		PaymentDetails:      paymentDetails,
+		Discounts:           discounts,
 	}
 
 	purchase, err := u.purchaseRepo.CreatePurchase(ctx, arg)
@@ -95,7 +179,7 @@ func (u *PurchaseUseCase) CreatePurchase(ctx context.Context, request *dto.Purch
 		return nil, errors.Wrap(err, "failed to create purchase")
 	}
 
-	response := converter.ToPurchaseResponse(purchase, purchasedItems, paymentDetails)
+	response := converter.ToPurchaseResponse(purchase, purchasedItems, paymentDetails, discounts)
 	return &response, nil
 }
 
@@ -135,3 +219,38 @@ func (u *PurchaseUseCase) CreatePayment(ctx context.Context, purchaseId int, req
 
 	return nil
 }
+
+func (u *PurchaseUseCase) ListPurchases(ctx context.Context, payload *dto.PurchaseListPayload) ([]dto.PurchaseDetailResponse, error) {
+	arg := repository.ListPurchasesParams{
+		SellerID: payload.SellerID,
+		Paid:     payload.Paid,
+		Limit:    payload.Limit,
+		Offset:   payload.Offset,
+	}
+
+	purchases, err := u.purchaseRepo.ListPurchases(ctx, arg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchases")
+	}
+
+	purchaseIDs := make([]int, 0, len(purchases))
+	for _, purchase := range purchases {
+		purchaseIDs = append(purchaseIDs, purchase.ID)
+	}
+
+	paymentDetails, err := u.purchaseRepo.GetPurchasePaymentDetails(ctx, purchaseIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get payment details")
+	}
+
+	paymentDetailsMap := make(map[int][]model.PurchasePaymentDetail)
+	for _, detail := range paymentDetails {
+		paymentDetailsMap[detail.PurchaseID] = append(paymentDetailsMap[detail.PurchaseID], detail)
+	}
+
+	responses := make([]dto.PurchaseDetailResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		responses = append(responses, converter.ToPurchaseDetailResponse(purchase, paymentDetailsMap[purchase.ID]))
+	}
+	return responses, nil
+}
@@ -2,10 +2,20 @@ package routes
 
 import (
 	"net/http"
+	alert_handler "tutup-lapak/internal/alert/handler"
+	api_key_handler "tutup-lapak/internal/apikey/handler"
+	api_key_model "tutup-lapak/internal/apikey/model"
+	auth_handler "tutup-lapak/internal/auth/handler"
+	auth_model "tutup-lapak/internal/auth/model"
+	bank_account_handler "tutup-lapak/internal/bankaccount/handler"
+	category_handler "tutup-lapak/internal/category/handler"
 	file_handler "tutup-lapak/internal/file/handler"
 	custom_middleware "tutup-lapak/internal/middleware"
 	product_handler "tutup-lapak/internal/product/handler"
+	promotion_handler "tutup-lapak/internal/promotion/handler"
 	purchase_handler "tutup-lapak/internal/purchase/handler"
+	security_usecase "tutup-lapak/internal/security/usecase"
+	user_handler "tutup-lapak/internal/user/handler"
 	"tutup-lapak/pkg/response"
 
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -14,12 +24,20 @@ import (
 )
 
 type RouteConfig struct {
-	App             *echo.Echo
-	S3Uploader      *manager.Uploader
-	Middleware      *custom_middleware.AuthConfig
-	ProductHandler  *product_handler.ProductHandler
-	PurchaseHandler *purchase_handler.PurchaseHandler
-	FileHandler     *file_handler.FileHandler
+	App                *echo.Echo
+	S3Uploader         *manager.Uploader
+	Middleware         *custom_middleware.AuthConfig
+	RateLimit          *custom_middleware.RateLimitConfig
+	AuthHandler        *auth_handler.AuthHandler
+	ProductHandler     *product_handler.ProductHandler
+	PurchaseHandler    *purchase_handler.PurchaseHandler
+	FileHandler        *file_handler.FileHandler
+	UserHandler        *user_handler.UserHandler
+	BankAccountHandler *bank_account_handler.BankAccountHandler
+	APIKeyHandler      *api_key_handler.APIKeyHandler
+	CategoryHandler    *category_handler.CategoryHandler
+	PromotionHandler   *promotion_handler.PromotionHandler
+	AlertHandler       *alert_handler.AlertHandler
 }
 
 func (r *RouteConfig) SetupRoutes() {
@@ -30,30 +48,95 @@ func (r *RouteConfig) SetupRoutes() {
 		})
 	})
 
+	r.App.GET("/.well-known/jwks.json", r.AuthHandler.JWKS)
+
 	v1 := r.App.Group("/v1")
 	r.setupPublicRoutes(v1)
 	r.setupAuthRoutes(v1, r.Middleware.Authenticate())
 }
 
 func (r *RouteConfig) setupPublicRoutes(group *echo.Group) {
+	group.POST("/register", r.AuthHandler.Register)
+	group.POST("/login", r.AuthHandler.Login)
+	group.POST("/token/refresh", r.AuthHandler.RefreshToken)
+	group.POST("/password/forgot", r.AuthHandler.ForgotPassword, r.RateLimit.LimitRequests("password-forgot", security_usecase.SendPolicy))
+	group.POST("/password/reset", r.AuthHandler.ResetPassword, r.RateLimit.LimitFailures("password-reset", security_usecase.ClientPolicy))
 	group.GET("/product", r.ProductHandler.GetProducts)
+	group.GET("/product/:productId/variant", r.ProductHandler.ListVariants)
+	group.GET("/product/:productId/price-history", r.ProductHandler.GetPriceHistory)
+	group.GET("/category", r.CategoryHandler.ListCategories)
 	group.POST("/purchase", r.PurchaseHandler.CreatePurchase)
-	group.POST("/purchase/:purchaseId", r.PurchaseHandler.CreatePayment)
+	group.POST("/purchase/:purchaseId", r.PurchaseHandler.CreatePayment, r.RateLimit.LimitFailures("payment", security_usecase.ClientPolicy))
 }
 
 func (r *RouteConfig) setupAuthRoutes(group *echo.Group, m echo.MiddlewareFunc) {
-	r.setupProductAuthRoutes(group, m)
+	r.setupSessionAuthRoutes(group, m)
+	r.setupProductAuthRoutes(group, r.Middleware.AuthenticateWithAPIKey())
+	r.setupUserAuthRoutes(group, m)
+	r.setupAdminRoutes(group, m)
+}
+
+func (r *RouteConfig) setupSessionAuthRoutes(group *echo.Group, m echo.MiddlewareFunc) {
+	group.POST("/logout", r.AuthHandler.Logout, m)
 }
 
+// setupProductAuthRoutes registers routes reachable with API keys as well as
+// session tokens. Every route states the scope it needs.
 func (r *RouteConfig) setupProductAuthRoutes(group *echo.Group, m echo.MiddlewareFunc) {
+	catalogRead := r.Middleware.RequireScope(api_key_model.ScopeCatalogRead)
+	productWrite := r.Middleware.RequireScope(api_key_model.ScopeProductWrite)
+
 	product := group.Group("/product")
-	// product.POST("", r.ProductHandler.CreateProduct, m)
-	// product.PATCH("/:productId", r.ProductHandler.UpdateProduct, m)
-	// product.DELETE("/:productId", r.ProductHandler.DeleteProduct, m)
-	// group.POST("/file", r.FileHandler.UploadFile, m)
-	product.POST("", r.ProductHandler.CreateProduct)
-	product.PATCH("/:productId", r.ProductHandler.UpdateProduct)
-	product.DELETE("/:productId", r.ProductHandler.DeleteProduct)
-	group.POST("/file", r.FileHandler.UploadFile)
+	product.GET("/sku/:sku", r.ProductHandler.GetProductBySku, m, catalogRead)
+	product.GET("/export", r.ProductHandler.ExportProducts, m, catalogRead)
+	product.POST("/import", r.ProductHandler.ImportProducts, m, productWrite)
+	product.POST("", r.ProductHandler.CreateProduct, m, productWrite)
+	product.PATCH("/:productId", r.ProductHandler.UpdateProduct, m, productWrite)
+	product.DELETE("/:productId", r.ProductHandler.DeleteProduct, m, productWrite)
+	product.PUT("/:productId/images", r.ProductHandler.SetProductImages, m, productWrite)
+	product.POST("/:productId/restore", r.ProductHandler.RestoreProduct, m, productWrite)
+	product.POST("/:productId/variant", r.ProductHandler.CreateVariant, m, productWrite)
+	product.PATCH("/:productId/variant/:variantId", r.ProductHandler.UpdateVariant, m, productWrite)
+	product.DELETE("/:productId/variant/:variantId", r.ProductHandler.DeleteVariant, m, productWrite)
+	product.GET("/:productId/price-schedule", r.ProductHandler.ListPriceSchedules, m, catalogRead)
+	product.POST("/:productId/price-schedule", r.ProductHandler.CreatePriceSchedule, m, productWrite)
+	product.DELETE("/:productId/price-schedule/:scheduleId", r.ProductHandler.CancelPriceSchedule, m, productWrite)
+	product.PUT("/:productId/stock-threshold", r.AlertHandler.SetStockThreshold, m, productWrite)
+	group.POST("/file", r.FileHandler.UploadFile, m, productWrite)
+	group.GET("/alerts", r.AlertHandler.ListStockAlerts, m, catalogRead)
+}
+
+func (r *RouteConfig) setupUserAuthRoutes(group *echo.Group, m echo.MiddlewareFunc) {
+	user := group.Group("/user")
+	user.GET("", r.UserHandler.GetUser, m)
+	user.PATCH("", r.UserHandler.UpdateUser, m)
+	user.POST("/verify/request", r.AuthHandler.RequestVerification, m)
+	user.POST("/verify/confirm", r.AuthHandler.ConfirmVerification, m)
+	user.GET("/bank-account", r.BankAccountHandler.ListBankAccounts, m)
+	user.POST("/bank-account", r.BankAccountHandler.CreateBankAccount, m)
+	user.PATCH("/bank-account/:bankAccountId", r.BankAccountHandler.UpdateBankAccount, m)
+	user.DELETE("/bank-account/:bankAccountId", r.BankAccountHandler.DeleteBankAccount, m)
+	user.GET("/api-key", r.APIKeyHandler.ListAPIKeys, m)
+	user.POST("/api-key", r.APIKeyHandler.CreateAPIKey, m)
+	user.DELETE("/api-key/:apiKeyId", r.APIKeyHandler.RevokeAPIKey, m)
+	user.GET("/promotion", r.PromotionHandler.ListPromotions, m)
+	user.POST("/promotion", r.PromotionHandler.CreatePromotion, m)
+	user.PATCH("/promotion/:promotionId", r.PromotionHandler.UpdatePromotion, m)
+}
+
+// setupAdminRoutes registers moderation routes. Every route states the roles
+// allowed to call it right after the Authenticate middleware.
+func (r *RouteConfig) setupAdminRoutes(group *echo.Group, m echo.MiddlewareFunc) {
+	adminOnly := r.Middleware.RequireRole(auth_model.RoleAdmin)
+	adminOrSupport := r.Middleware.RequireRole(auth_model.RoleAdmin, auth_model.RoleSupport)
 
+	admin := group.Group("/admin")
+	admin.GET("/purchase", r.PurchaseHandler.ListPurchases, m, adminOrSupport)
+	admin.DELETE("/product/:productId", r.ProductHandler.ModerateDeleteProduct, m, adminOnly)
+	admin.POST("/product/purge", r.ProductHandler.PurgeProducts, m, adminOnly)
+	admin.GET("/category", r.CategoryHandler.ListAllCategories, m, adminOnly)
+	admin.POST("/category", r.CategoryHandler.CreateCategory, m, adminOnly)
+	admin.PATCH("/category/:categoryId", r.CategoryHandler.UpdateCategory, m, adminOnly)
+	admin.POST("/user/:userId/disable", r.UserHandler.DisableUser, m, adminOnly)
+	admin.POST("/user/:userId/enable", r.UserHandler.EnableUser, m, adminOnly)
 }
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	EventLoginSucceeded = "login_succeeded"
+	EventLoginFailed    = "login_failed"
+	EventLoginLocked    = "login_locked"
+	EventLoginBlocked   = "login_blocked"
+)
+
+// LimitPolicy allows MaxFailures failures within ResetAfter, then locks the
+// key for BaseLockout, doubling on every further failure up to MaxLockout.
+type LimitPolicy struct {
+	MaxFailures int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	ResetAfter  time.Duration
+}
+
+type RateLimit struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+type SecurityEvent struct {
+	SellerID   *int
+	EventType  string
+	Identifier string
+	IPAddress  string
+	UserAgent  string
+}
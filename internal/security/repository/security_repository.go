@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"tutup-lapak/internal/security/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SecurityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSecurityRepository(pool *pgxpool.Pool) *SecurityRepository {
+	return &SecurityRepository{pool: pool}
+}
+
+const getRateLimitQuery = `-- name: GetRateLimit :one
+SELECT key, failures, last_failure_at, locked_until FROM rate_limits
+WHERE key = $1
+LIMIT 1
+`
+
+func (r *SecurityRepository) GetRateLimit(ctx context.Context, key string) (model.RateLimit, error) {
+	row := r.pool.QueryRow(ctx, getRateLimitQuery, key)
+
+	var i model.RateLimit
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const registerFailureQuery = `-- name: RegisterFailure :one
+INSERT INTO rate_limits (key, failures, last_failure_at) VALUES ($1, 1, NOW())
+ON CONFLICT (key) DO UPDATE SET
+	failures = CASE
+		WHEN rate_limits.last_failure_at < NOW() - make_interval(secs => $2)
+			AND (rate_limits.locked_until IS NULL OR rate_limits.locked_until < NOW())
+		THEN 1
+		ELSE rate_limits.failures + 1
+	END,
+	last_failure_at = NOW()
+RETURNING key, failures, last_failure_at, locked_until
+`
+
+// RegisterFailure atomically counts a failure for key. The counter starts
+// over when the previous failure is older than resetAfter.
+func (r *SecurityRepository) RegisterFailure(ctx context.Context, key string, resetAfter time.Duration) (model.RateLimit, error) {
+	row := r.pool.QueryRow(ctx, registerFailureQuery, key, resetAfter.Seconds())
+
+	var i model.RateLimit
+	err := row.Scan(
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const lockQuery = `-- name: Lock :exec
+UPDATE rate_limits SET locked_until = $2 WHERE key = $1
+`
+
+func (r *SecurityRepository) Lock(ctx context.Context, key string, lockedUntil time.Time) error {
+	_, err := r.pool.Exec(ctx, lockQuery, key, lockedUntil)
+	return err
+}
+
+const resetRateLimitQuery = `-- name: ResetRateLimit :exec
+DELETE FROM rate_limits WHERE key = $1
+`
+
+func (r *SecurityRepository) ResetRateLimit(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, resetRateLimitQuery, key)
+	return err
+}
+
+const deleteExpiredRateLimitsQuery = `-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limits
+WHERE last_failure_at < NOW() - make_interval(secs => $1)
+	AND (locked_until IS NULL OR locked_until < NOW())
+`
+
+// DeleteExpiredRateLimits deletes the counters that are unlocked and whose
+// last failure is older than resetAfter, they would start over anyway.
+func (r *SecurityRepository) DeleteExpiredRateLimits(ctx context.Context, resetAfter time.Duration) (int64, error) {
+	result, err := r.pool.Exec(ctx, deleteExpiredRateLimitsQuery, resetAfter.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertSecurityEventQuery = `-- name: InsertSecurityEvent :exec
+INSERT INTO security_events (seller_id, event_type, identifier, ip_address, user_agent) VALUES ($1, $2, $3, $4, $5)
+`
+
+func (r *SecurityRepository) InsertSecurityEvent(ctx context.Context, event model.SecurityEvent) error {
+	_, err := r.pool.Exec(ctx, insertSecurityEventQuery,
+		event.SellerID,
+		event.EventType,
+		event.Identifier,
+		event.IPAddress,
+		event.UserAgent,
+	)
+	return err
+}
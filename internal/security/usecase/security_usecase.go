@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"tutup-lapak/internal/security/model"
+	"tutup-lapak/internal/security/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxIdentifierLength = 255
+	maxUserAgentLength  = 512
+)
+
+var (
+	// AccountPolicy guards a single account against password guessing.
+	AccountPolicy = model.LimitPolicy{
+		MaxFailures: 5,
+		BaseLockout: 30 * time.Second,
+		MaxLockout:  time.Hour,
+		ResetAfter:  15 * time.Minute,
+	}
+	// ClientPolicy guards against one client spraying many accounts or IDs.
+	ClientPolicy = model.LimitPolicy{
+		MaxFailures: 20,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  15 * time.Minute,
+	}
+	// SendPolicy caps the codes sent to one account or for one client, every
+	// send counts whether it succeeds or not.
+	SendPolicy = model.LimitPolicy{
+		MaxFailures: 5,
+		BaseLockout: 15 * time.Minute,
+		MaxLockout:  24 * time.Hour,
+		ResetAfter:  time.Hour,
+	}
+)
+
+type SecurityUsecase struct {
+	repo *repository.SecurityRepository
+}
+
+func NewSecurityUsecase(repo *repository.SecurityRepository) *SecurityUsecase {
+	return &SecurityUsecase{
+		repo: repo,
+	}
+}
+
+// Check returns ErrTooManyRequests when any of the keys is locked.
+func (u *SecurityUsecase) Check(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		rateLimit, err := u.repo.GetRateLimit(ctx, key)
+		if err != nil {
+			if errors.Is(err, customErrors.ErrNotFound) {
+				continue
+			}
+			return errors.Wrap(err, "failed to get rate limit")
+		}
+
+		if rateLimit.LockedUntil != nil && rateLimit.LockedUntil.After(time.Now()) {
+			retryAfter := int(math.Ceil(time.Until(*rateLimit.LockedUntil).Seconds()))
+			return errors.Wrap(customErrors.ErrTooManyRequests, fmt.Sprintf("too many failed attempts, try again in %d seconds", retryAfter))
+		}
+	}
+	return nil
+}
+
+// RegisterFailure counts a failure for key and locks it with exponential
+// backoff once the policy's allowance is used up.
+func (u *SecurityUsecase) RegisterFailure(ctx context.Context, key string, policy model.LimitPolicy) error {
+	rateLimit, err := u.repo.RegisterFailure(ctx, key, policy.ResetAfter)
+	if err != nil {
+		return errors.Wrap(err, "failed to register failure")
+	}
+
+	if rateLimit.Failures < policy.MaxFailures {
+		return nil
+	}
+
+	if err := u.repo.Lock(ctx, key, time.Now().Add(lockoutFor(rateLimit.Failures, policy))); err != nil {
+		return errors.Wrap(err, "failed to lock")
+	}
+	return nil
+}
+
+func (u *SecurityUsecase) Reset(ctx context.Context, key string) error {
+	if err := u.repo.ResetRateLimit(ctx, key); err != nil {
+		return errors.Wrap(err, "failed to reset rate limit")
+	}
+	return nil
+}
+
+// PurgeRateLimits deletes the counters no policy would still count on.
+func (u *SecurityUsecase) PurgeRateLimits(ctx context.Context) (int64, error) {
+	resetAfter := max(AccountPolicy.ResetAfter, ClientPolicy.ResetAfter, SendPolicy.ResetAfter)
+
+	deleted, err := u.repo.DeleteExpiredRateLimits(ctx, resetAfter)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to purge rate limits")
+	}
+	return deleted, nil
+}
+
+func (u *SecurityUsecase) RecordEvent(ctx context.Context, event model.SecurityEvent) error {
+	if len(event.Identifier) > maxIdentifierLength {
+		event.Identifier = event.Identifier[:maxIdentifierLength]
+	}
+	if len(event.UserAgent) > maxUserAgentLength {
+		event.UserAgent = event.UserAgent[:maxUserAgentLength]
+	}
+
+	if err := u.repo.InsertSecurityEvent(ctx, event); err != nil {
+		return errors.Wrap(err, "failed to record security event")
+	}
+	return nil
+}
+
+func lockoutFor(failures int, policy model.LimitPolicy) time.Duration {
+	exponent := min(failures-policy.MaxFailures, 30)
+	lockout := policy.BaseLockout * time.Duration(1<<exponent)
+	if lockout <= 0 || lockout > policy.MaxLockout {
+		return policy.MaxLockout
+	}
+	return lockout
+}
@@ -0,0 +1,23 @@
+package dto
+
+// UserUpdateRequest edits the profile. The bank fields edit the default bank
+// account, all three are required when the seller has none yet.
+type UserUpdateRequest struct {
+	FileID            *string `json:"fileId" validate:"omitnil,number"`
+	Email             *string `json:"email" validate:"omitnil,email"`
+	Phone             *string `json:"phone" validate:"omitnil,phone_number"`
+	BankAccountName   *string `json:"bankAccountName" validate:"omitnil,min=4,max=32"`
+	BankAccountHolder *string `json:"bankAccountHolder" validate:"omitnil,min=4,max=32"`
+	BankAccountNumber *string `json:"bankAccountNumber" validate:"omitnil,min=4,max=32"`
+}
+
+type UserResponse struct {
+	Email             string `json:"email"`
+	Phone             string `json:"phone"`
+	FileID            string `json:"fileId"`
+	FileURI           string `json:"fileUri"`
+	FileThumbnailURI  string `json:"fileThumbnailUri"`
+	BankAccountName   string `json:"bankAccountName"`
+	BankAccountHolder string `json:"bankAccountHolder"`
+	BankAccountNumber string `json:"bankAccountNumber"`
+}
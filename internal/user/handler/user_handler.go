@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	custom_middleware "tutup-lapak/internal/middleware"
+	"tutup-lapak/internal/user/dto"
+	"tutup-lapak/internal/user/usecase"
+	customErrors "tutup-lapak/pkg/custom-errors"
+	"tutup-lapak/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type UserHandler struct {
+	UseCase  *usecase.UserUsecase
+	Validate *validator.Validate
+}
+
+func NewUserHandler(useCase *usecase.UserUsecase, validate *validator.Validate) *UserHandler {
+	return &UserHandler{
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *UserHandler) GetUser(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	user, err := h.UseCase.GetUser(ctx.Request().Context(), sellerID)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) UpdateUser(ctx echo.Context) error {
+	sellerID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	var request = new(dto.UserUpdateRequest)
+
+	if err := ctx.Bind(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		err = errors.Wrap(customErrors.ErrBadRequest, err.Error())
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	user, err := h.UseCase.UpdateUser(ctx.Request().Context(), sellerID, request)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) DisableUser(ctx echo.Context) error {
+	return h.setUserDisabled(ctx, true)
+}
+
+func (h *UserHandler) EnableUser(ctx echo.Context) error {
+	return h.setUserDisabled(ctx, false)
+}
+
+func (h *UserHandler) setUserDisabled(ctx echo.Context, disabled bool) error {
+	id, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(customErrors.ErrNotFound))
+	}
+
+	adminID, err := custom_middleware.GetSellerID(ctx)
+	if err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+	if id == adminID {
+		err = errors.Wrap(customErrors.ErrBadRequest, "cannot change your own account status")
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	if err := h.UseCase.SetUserDisabled(ctx.Request().Context(), id, disabled); err != nil {
+		return ctx.JSON(response.WriteErrorResponse(err))
+	}
+
+	message := "User is enabled"
+	if disabled {
+		message = "User is disabled"
+	}
+
+	return ctx.JSON(http.StatusOK, response.BaseResponse{
+		Status:  "OK",
+		Message: message,
+	})
+}
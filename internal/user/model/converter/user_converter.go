@@ -0,0 +1,20 @@
+package converter
+
+import (
+	"tutup-lapak/internal/user/dto"
+	"tutup-lapak/internal/user/model"
+	"tutup-lapak/pkg/helper"
+)
+
+func ToUserResponse(seller model.Seller) dto.UserResponse {
+	return dto.UserResponse{
+		Email:             helper.DerefString(seller.Email, ""),
+		Phone:             helper.DerefString(seller.PhoneNumber, ""),
+		FileID:            helper.DerefString(seller.FileID, ""),
+		FileURI:           helper.DerefString(seller.FileURI, ""),
+		FileThumbnailURI:  helper.DerefString(seller.FileThumbnailURI, ""),
+		BankAccountName:   helper.DerefString(seller.BankAccountName, ""),
+		BankAccountHolder: helper.DerefString(seller.BankAccountHolder, ""),
+		BankAccountNumber: helper.DerefString(seller.BankAccountNumber, ""),
+	}
+}
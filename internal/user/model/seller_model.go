@@ -0,0 +1,13 @@
+package model
+
+type Seller struct {
+	ID                int
+	Email             *string
+	PhoneNumber       *string
+	FileID            *string
+	FileURI           *string
+	FileThumbnailURI  *string
+	BankAccountName   *string
+	BankAccountHolder *string
+	BankAccountNumber *string
+}
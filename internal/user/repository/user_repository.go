@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+
+	authModel "tutup-lapak/internal/auth/model"
+	"tutup-lapak/internal/user/model"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+type UserRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
+	return &UserRepository{pool: pool}
+}
+
+const getSellerQuery = `-- name: GetSeller :one
+SELECT
+	s.id,
+	s.email,
+	s.phone_number,
+	f.id::TEXT file_id,
+	f.uri file_uri,
+	f.thumbnail_uri file_thumbnail_uri,
+	b.bank_account_name,
+	b.bank_account_holder,
+	b.bank_account_number
+FROM sellers s
+LEFT JOIN files f ON f.id = s.file_id
+LEFT JOIN seller_bank_accounts b ON b.seller_id = s.id AND b.is_default
+WHERE s.id = $1
+LIMIT 1
+`
+
+func (r *UserRepository) GetSeller(ctx context.Context, sellerID int) (model.Seller, error) {
+	row := r.pool.QueryRow(ctx, getSellerQuery, sellerID)
+
+	var i model.Seller
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.FileID,
+		&i.FileURI,
+		&i.FileThumbnailURI,
+		&i.BankAccountName,
+		&i.BankAccountHolder,
+		&i.BankAccountNumber,
+	)
+	return i, err
+}
+
+const updateSellerQuery = `-- name: UpdateSeller :exec
+UPDATE sellers
+SET
+	file_id = COALESCE($2::BIGINT, file_id),
+	email = COALESCE($3, email),
+	email_verified_at = CASE WHEN $3 IS NULL OR $3 = email THEN email_verified_at END,
+	phone_number = COALESCE($4, phone_number),
+	phone_verified_at = CASE WHEN $4 IS NULL OR $4 = phone_number THEN phone_verified_at END
+WHERE id = $1
+`
+
+const updateDefaultBankAccountQuery = `-- name: UpdateDefaultBankAccount :execrows
+UPDATE seller_bank_accounts
+SET
+	bank_account_name = COALESCE($2, bank_account_name),
+	bank_account_holder = COALESCE($3, bank_account_holder),
+	bank_account_number = COALESCE($4, bank_account_number)
+WHERE seller_id = $1 AND is_default
+`
+
+const createDefaultBankAccountQuery = `-- name: CreateDefaultBankAccount :exec
+INSERT INTO seller_bank_accounts (seller_id, bank_account_name, bank_account_holder, bank_account_number, is_default)
+VALUES ($1, $2, $3, $4, TRUE)
+`
+
+var defaultBankAccountConstraints = map[string]string{
+	"idx_seller_bank_accounts_default": "default bank account was changed concurrently",
+}
+
+type UpdateSellerParams struct {
+	ID                int
+	FileID            *string
+	Email             *string
+	PhoneNumber       *string
+	BankAccountName   *string
+	BankAccountHolder *string
+	BankAccountNumber *string
+}
+
+// UpdateSeller updates the profile of a seller. Bank details go to the
+// default bank account, which payouts are made to, and create it when the
+// seller has none yet.
+func (r *UserRepository) UpdateSeller(ctx context.Context, arg UpdateSellerParams) (model.Seller, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return model.Seller{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, updateSellerQuery,
+		arg.ID,
+		arg.FileID,
+		arg.Email,
+		arg.PhoneNumber,
+	)
+	if err != nil {
+		return model.Seller{}, customErrors.HandlePgConstraintError(err, "failed to update seller", authModel.SellerConstraints)
+	}
+
+	if arg.BankAccountName != nil || arg.BankAccountHolder != nil || arg.BankAccountNumber != nil {
+		if err := updateDefaultBankAccount(ctx, tx, arg); err != nil {
+			return model.Seller{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.Seller{}, err
+	}
+
+	return r.GetSeller(ctx, arg.ID)
+}
+
+func updateDefaultBankAccount(ctx context.Context, tx pgx.Tx, arg UpdateSellerParams) error {
+	result, err := tx.Exec(ctx, updateDefaultBankAccountQuery,
+		arg.ID,
+		arg.BankAccountName,
+		arg.BankAccountHolder,
+		arg.BankAccountNumber,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 1 {
+		return nil
+	}
+
+	if arg.BankAccountName == nil || arg.BankAccountHolder == nil || arg.BankAccountNumber == nil {
+		return errors.Wrap(customErrors.ErrBadRequest, "bankAccountName, bankAccountHolder and bankAccountNumber are required to add a bank account")
+	}
+
+	_, err = tx.Exec(ctx, createDefaultBankAccountQuery,
+		arg.ID,
+		arg.BankAccountName,
+		arg.BankAccountHolder,
+		arg.BankAccountNumber,
+	)
+	if err != nil {
+		return customErrors.HandlePgConstraintError(err, "failed to create bank account", defaultBankAccountConstraints)
+	}
+	return nil
+}
+
+const setSellerDisabledQuery = `-- name: SetSellerDisabled :execrows
+UPDATE sellers
+SET disabled_at = CASE WHEN $2::BOOLEAN THEN COALESCE(disabled_at, NOW()) END
+WHERE id = $1
+`
+
+const revokeSellerSessionsQuery = `-- name: RevokeSellerSessions :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE seller_id = $1 AND revoked_at IS NULL
+`
+
+// SetSellerDisabled disables or re-enables a seller. Disabling also revokes
+// every session so issued tokens stop working immediately.
+func (r *UserRepository) SetSellerDisabled(ctx context.Context, sellerID int, disabled bool) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, setSellerDisabledQuery, sellerID, disabled)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() != 1 {
+		return customErrors.ErrNotFound
+	}
+
+	if disabled {
+		if _, err := tx.Exec(ctx, revokeSellerSessionsQuery, sellerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
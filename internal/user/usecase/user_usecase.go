@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	fileRepository "tutup-lapak/internal/file/repository"
+	"tutup-lapak/internal/user/dto"
+	"tutup-lapak/internal/user/model/converter"
+	"tutup-lapak/internal/user/repository"
+	customErrors "tutup-lapak/pkg/custom-errors"
+
+	"github.com/pkg/errors"
+)
+
+type UserUsecase struct {
+	userRepo *repository.UserRepository
+	fileRepo *fileRepository.FileRepository
+}
+
+func NewUserUsecase(userRepo *repository.UserRepository, fileRepo *fileRepository.FileRepository) *UserUsecase {
+	return &UserUsecase{
+		userRepo: userRepo,
+		fileRepo: fileRepo,
+	}
+}
+
+func (u *UserUsecase) GetUser(ctx context.Context, sellerID int) (*dto.UserResponse, error) {
+	seller, err := u.userRepo.GetSeller(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return nil, errors.Wrap(customErrors.ErrNotFound, "seller not found")
+		}
+		return nil, errors.Wrap(err, "failed to get seller")
+	}
+
+	response := converter.ToUserResponse(seller)
+	return &response, nil
+}
+
+func (u *UserUsecase) UpdateUser(ctx context.Context, sellerID int, request *dto.UserUpdateRequest) (*dto.UserResponse, error) {
+	if request.FileID != nil {
+		if err := u.checkFileOwnership(ctx, sellerID, *request.FileID); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*request.Email))
+		request.Email = &email
+	}
+
+	arg := repository.UpdateSellerParams{
+		ID:                sellerID,
+		FileID:            request.FileID,
+		Email:             request.Email,
+		PhoneNumber:       request.Phone,
+		BankAccountName:   request.BankAccountName,
+		BankAccountHolder: request.BankAccountHolder,
+		BankAccountNumber: request.BankAccountNumber,
+	}
+
+	seller, err := u.userRepo.UpdateSeller(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	response := converter.ToUserResponse(seller)
+	return &response, nil
+}
+
+func (u *UserUsecase) SetUserDisabled(ctx context.Context, sellerID int, disabled bool) error {
+	err := u.userRepo.SetSellerDisabled(ctx, sellerID, disabled)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return errors.Wrap(customErrors.ErrNotFound, "seller not found")
+		}
+		return errors.Wrap(err, "failed to update seller")
+	}
+	return nil
+}
+
+func (u *UserUsecase) checkFileOwnership(ctx context.Context, sellerID int, fileIDStr string) error {
+	fileID, err := strconv.Atoi(fileIDStr)
+	if err != nil {
+		return errors.Wrap(customErrors.ErrBadRequest, "invalid file ID")
+	}
+
+	file, err := u.fileRepo.GetFile(ctx, fileID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrNotFound) {
+			return errors.Wrap(customErrors.ErrBadRequest, "fileId not exists")
+		}
+		return errors.Wrap(err, "failed to get file")
+	}
+
+	if file.SellerID == nil || *file.SellerID != sellerID {
+		return errors.Wrap(customErrors.ErrBadRequest, "fileId not exists")
+	}
+
+	return nil
+}
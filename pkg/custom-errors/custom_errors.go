@@ -9,13 +9,17 @@ import (
 const (
 	ForeignKeyViolation = "23503"
 	UniqueViolation     = "23505"
+	ExclusionViolation  = "23P01"
 )
 
 var (
-	ErrNotFound     = pgx.ErrNoRows
-	ErrConflict     = errors.New("conflict")
-	ErrBadRequest   = errors.New("bad request")
-	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound           = pgx.ErrNoRows
+	ErrConflict           = errors.New("conflict")
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrPreconditionFailed = errors.New("precondition failed")
 )
 
 func GetPgErrCode(err error) string {
@@ -26,6 +30,14 @@ func GetPgErrCode(err error) string {
 	return ""
 }
 
+func GetPgConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
 func HandlePgError(err error, msg string) error {
 	if err == ErrNotFound {
 		return ErrNotFound
@@ -34,10 +46,22 @@ func HandlePgError(err error, msg string) error {
 	code := GetPgErrCode(err)
 	switch code {
 	case UniqueViolation:
-		return errors.Wrap(ErrConflict, "email already exists")
+		return errors.Wrap(ErrConflict, "resource already exists")
 	case ForeignKeyViolation:
 		return errors.Wrap(ErrBadRequest, "fileId not exists")
 	default:
 		return errors.Wrap(err, msg)
 	}
 }
+
+// HandlePgConstraintError resolves unique and exclusion violations to the
+// message registered for the violated constraint and falls back to
+// HandlePgError otherwise.
+func HandlePgConstraintError(err error, msg string, constraints map[string]string) error {
+	if code := GetPgErrCode(err); code == UniqueViolation || code == ExclusionViolation {
+		if conflictMsg, found := constraints[GetPgConstraintName(err)]; found {
+			return errors.Wrap(ErrConflict, conflictMsg)
+		}
+	}
+	return HandlePgError(err, msg)
+}
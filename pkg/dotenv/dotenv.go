@@ -8,10 +8,23 @@ import (
 
 type Env struct {
 	JWT_SECRET         string
+	JWT_KEYS           string
+	JWT_ACTIVE_KID     string
 	AWS_S3_REGION      string
 	AWS_S3_ID          string
 	AWS_S3_SECRET_KEY  string
 	AWS_S3_BUCKET_NAME string
+	NOTIFIER           string
+	NOTIFIER_FILE      string
+	SMTP_HOST          string
+	SMTP_PORT          string
+	SMTP_USERNAME      string
+	SMTP_PASSWORD      string
+	SMTP_FROM          string
+	ALERT_NOTIFIER     string
+	ALERT_WEBHOOK_URL  string
+	ALERT_WEBHOOK_KEY  string
+	TRUSTED_PROXIES    string
 }
 
 func LoadEnv() (*Env, error) {
@@ -22,9 +35,22 @@ func LoadEnv() (*Env, error) {
 
 	return &Env{
 		JWT_SECRET:         os.Getenv("JWT_SECRET"),
+		JWT_KEYS:           os.Getenv("JWT_KEYS"),
+		JWT_ACTIVE_KID:     os.Getenv("JWT_ACTIVE_KID"),
 		AWS_S3_REGION:      os.Getenv("S3_REGION"),
 		AWS_S3_ID:          os.Getenv("S3_ID"),
 		AWS_S3_SECRET_KEY:  os.Getenv("S3_SECRET_KEY"),
 		AWS_S3_BUCKET_NAME: os.Getenv("S3_BUCKET_NAME"),
+		NOTIFIER:           os.Getenv("NOTIFIER"),
+		NOTIFIER_FILE:      os.Getenv("NOTIFIER_FILE"),
+		SMTP_HOST:          os.Getenv("SMTP_HOST"),
+		SMTP_PORT:          os.Getenv("SMTP_PORT"),
+		SMTP_USERNAME:      os.Getenv("SMTP_USERNAME"),
+		SMTP_PASSWORD:      os.Getenv("SMTP_PASSWORD"),
+		SMTP_FROM:          os.Getenv("SMTP_FROM"),
+		ALERT_NOTIFIER:     os.Getenv("ALERT_NOTIFIER"),
+		ALERT_WEBHOOK_URL:  os.Getenv("ALERT_WEBHOOK_URL"),
+		ALERT_WEBHOOK_KEY:  os.Getenv("ALERT_WEBHOOK_KEY"),
+		TRUSTED_PROXIES:    os.Getenv("TRUSTED_PROXIES"),
 	}, nil
 }
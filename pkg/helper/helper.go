@@ -19,6 +19,13 @@ func DerefInt(i *int, fallback int) int {
 	return *i
 }
 
+func NilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func DerefGeneric[T any](value interface{}, fallback T) T {
 	val := reflect.ValueOf(value)
 
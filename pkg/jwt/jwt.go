@@ -8,20 +8,41 @@ import (
 )
 
 type JWTClaim struct {
-	ID int
+	ID        int
+	Role      string
+	SessionID string
 	jwt.RegisteredClaims
 }
 
-func CreateToken(id int, secret string) (string, error) {
-	secretByte := []byte(secret)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaim{
-		ID: id,
+// KeySet holds every key accepted for verification, indexed by kid, and the
+// key used to sign new tokens. Tokens without a kid header are verified with
+// the HS256 secret, if one is configured.
+type KeySet struct {
+	keys     map[string]*Key
+	activeID string
+}
+
+func (k *KeySet) CreateToken(id int, role string, sessionID string, ttl time.Duration) (string, error) {
+	signingKey, found := k.keys[k.activeID]
+	if !found || signingKey.SigningKey == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(signingKey.Method, &JWTClaim{
+		ID:        id,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(8 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	})
+	if signingKey.ID != "" {
+		token.Header["kid"] = signingKey.ID
+	}
 
-	tokenStr, err := token.SignedString(secretByte)
+	tokenStr, err := token.SignedString(signingKey.SigningKey)
 	if err != nil {
 		return "", err
 	}
@@ -29,25 +50,37 @@ func CreateToken(id int, secret string) (string, error) {
 	return tokenStr, nil
 }
 
-func ClaimToken(token string, secret string) (*JWTClaim, error) {
-	secretByte := []byte(secret)
+func (k *KeySet) ClaimToken(token string) (*JWTClaim, error) {
 	jwtToken, err := jwt.ParseWithClaims(
 		token,
 		&JWTClaim{},
-		func(token *jwt.Token) (interface{}, error) { return secretByte, nil },
+		k.verifyKey,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if jwtToken.Method != jwt.SigningMethodHS256 {
+	claim, ok := jwtToken.Claims.(*JWTClaim)
+	if !ok || !jwtToken.Valid {
 		return nil, errors.New("Invalid token")
 	}
+	return claim, nil
+}
 
-	claim, ok := jwtToken.Claims.(*JWTClaim)
-	if !ok {
+func (k *KeySet) verifyKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, found := k.keys[kid]
+	if !found {
 		return nil, errors.New("Invalid token")
 	}
-	return claim, nil
+
+	// Reject tokens whose alg does not match the key, otherwise a public key
+	// could be abused as an HMAC secret.
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("Invalid token")
+	}
+
+	return key.VerifyKey, nil
 }
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// SigningKey is nil for verification-only keys, e.g. a retired key kept
+	// around until the tokens it signed have expired.
+	SigningKey interface{}
+	VerifyKey  interface{}
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet builds a KeySet from PEM files indexed by kid. The HS256 secret,
+// when not empty, stays accepted for tokens without a kid header and is used
+// for signing when activeID is empty.
+func NewKeySet(secret string, keyFiles map[string]string, activeID string) (*KeySet, error) {
+	keySet := &KeySet{
+		keys:     make(map[string]*Key),
+		activeID: activeID,
+	}
+
+	if secret != "" {
+		keySet.keys[""] = &Key{
+			Method:     jwt.SigningMethodHS256,
+			SigningKey: []byte(secret),
+			VerifyKey:  []byte(secret),
+		}
+	}
+
+	for kid, path := range keyFiles {
+		if kid == "" {
+			return nil, errors.Errorf("missing kid for key %s", path)
+		}
+
+		key, err := LoadPEMKey(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		keySet.keys[kid] = key
+	}
+
+	active, found := keySet.keys[activeID]
+	if !found || active.SigningKey == nil {
+		return nil, errors.Errorf("active key %q is not a signing key", activeID)
+	}
+
+	return keySet, nil
+}
+
+// LoadPEMKey reads an RSA or Ed25519 key from a PEM file. Private keys can
+// sign and verify, public keys can only verify.
+func LoadPEMKey(kid, path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read key %s", kid)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("key %s is not PEM encoded", kid)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("key %s has unsupported PEM type %s", kid, block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse key %s", kid)
+	}
+
+	key := &Key{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.SigningKey, key.VerifyKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.VerifyKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.SigningKey, key.VerifyKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.VerifyKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errors.Errorf("key %s has unsupported algorithm", kid)
+	}
+
+	return key, nil
+}
+
+// JWKS returns the public half of every asymmetric key. HMAC secrets are
+// never published.
+func (k *KeySet) JWKS() JWKSet {
+	jwks := JWKSet{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		if jwk, ok := toJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
+
+func toJWK(key *Key) (JWK, bool) {
+	jwk := JWK{
+		Kid: key.ID,
+		Use: "sig",
+		Alg: key.Method.Alg(),
+	}
+
+	switch pub := key.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier writes messages to the application log, for local development.
+type LogNotifier struct {
+	Log *logrus.Logger
+}
+
+func NewLogNotifier(log *logrus.Logger) *LogNotifier {
+	return &LogNotifier{Log: log}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, message Message) error {
+	n.Log.WithFields(logrus.Fields{
+		"channel": message.Channel,
+		"to":      message.To,
+		"subject": message.Subject,
+	}).Info(message.Body)
+	return nil
+}
+
+// FileNotifier appends messages as JSON lines to a file, for local
+// development and end-to-end tests that need to read the codes back.
+type FileNotifier struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{Path: path}
+}
+
+func (n *FileNotifier) Send(ctx context.Context, message Message) error {
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelPhone   = "phone"
+	ChannelWebhook = "webhook"
+	ChannelLog     = "log"
+)
+
+type Message struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier delivers a message to a seller over a single channel.
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// ChannelNotifier routes each message to the notifier registered for its
+// channel, e.g. email through SMTP and phone through the log.
+type ChannelNotifier map[string]Notifier
+
+func (n ChannelNotifier) Send(ctx context.Context, message Message) error {
+	channelNotifier, found := n[message.Channel]
+	if !found {
+		return errors.Errorf("no notifier for channel %s", message.Channel)
+	}
+	return channelNotifier.Send(ctx, message)
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SMTPNotifier sends email messages. Authentication is skipped when no
+// username is set so it can point at a local mail catcher.
+type SMTPNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPNotifier(host, port, username, password, from string) *SMTPNotifier {
+	return &SMTPNotifier{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, message Message) error {
+	if message.Channel != ChannelEmail {
+		return errors.Errorf("smtp notifier cannot send to channel %s", message.Channel)
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	headers := []string{
+		fmt.Sprintf("From: %s", n.From),
+		fmt.Sprintf("To: %s", message.To),
+		fmt.Sprintf("Subject: %s", message.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + message.Body
+
+	addr := net.JoinHostPort(n.Host, n.Port)
+	return smtp.SendMail(addr, auth, n.From, []string{message.To}, []byte(body))
+}
@@ -37,6 +37,21 @@ func WriteErrorResponse(err error) (int, BaseResponse) {
 			Status:  http.StatusText(http.StatusUnauthorized),
 			Message: msg,
 		}
+	case customErrors.ErrForbidden:
+		return http.StatusForbidden, BaseResponse{
+			Status:  http.StatusText(http.StatusForbidden),
+			Message: msg,
+		}
+	case customErrors.ErrTooManyRequests:
+		return http.StatusTooManyRequests, BaseResponse{
+			Status:  http.StatusText(http.StatusTooManyRequests),
+			Message: msg,
+		}
+	case customErrors.ErrPreconditionFailed:
+		return http.StatusPreconditionFailed, BaseResponse{
+			Status:  http.StatusText(http.StatusPreconditionFailed),
+			Message: msg,
+		}
 	default:
 		return http.StatusInternalServerError, BaseResponse{
 			Status:  http.StatusText(http.StatusInternalServerError),